@@ -0,0 +1,61 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"github.com/palantir/go-license/commoncmd"
+	"github.com/palantir/go-license/golicense"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	relicenseCmd = &cobra.Command{
+		Use:   "relicense --from=<header> --to=<header> [files|@file-list]",
+		Short: "Replace one license header with another",
+		Long: `Replace the "from" license header with the "to" license header in the specified files. Each header is
+specified as "default" (the default header of the configuration), the name of a custom header of the configuration or
+the path of a file that contains the header. If both headers contain {{YEAR}}, the year of the replaced header is kept.
+A summary is printed along with the files that have neither header.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if relicenseFromFlagVal == "" || relicenseToFlagVal == "" {
+				return errors.Errorf("--from and --to must be specified")
+			}
+			args, err := commoncmd.ResolveFiles(args, filesFromFlagVal, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			projectCfg, err := commoncmd.LoadConfig(cfgFlagVal)
+			if err != nil {
+				return err
+			}
+			projectParam, err := projectCfg.ToParam()
+			if err != nil {
+				return err
+			}
+			projectParam.ParseCheck = parseCheckFlagVal
+			from, err := commoncmd.ResolveHeader(relicenseFromFlagVal, projectParam)
+			if err != nil {
+				return errors.Wrapf(err, "invalid --from header")
+			}
+			to, err := commoncmd.ResolveHeader(relicenseToFlagVal, projectParam)
+			if err != nil {
+				return errors.Wrapf(err, "invalid --to header")
+			}
+			return golicense.RunRelicense(args, from, to, projectParam, cmd.OutOrStdout())
+		},
+	}
+
+	relicenseFromFlagVal string
+	relicenseToFlagVal   string
+)
+
+func init() {
+	relicenseCmd.Flags().StringVar(&relicenseFromFlagVal, "from", "", `the header to replace: "default", the name of a custom header or the path of a header file`)
+	relicenseCmd.Flags().StringVar(&relicenseToFlagVal, "to", "", `the header to replace it with: "default", the name of a custom header or the path of a header file`)
+	relicenseCmd.Flags().StringVar(&filesFromFlagVal, "files-from", "", `read the files to process from the specified file ("-" for stdin) in addition to the files specified as arguments (entries may be separated by newlines or NUL characters)`)
+	relicenseCmd.Flags().BoolVar(&parseCheckFlagVal, "parse-check", false, "check that modified Go files still parse and have the same package clause, build constraints and //go: directives, leaving all files unmodified if any file fails the check")
+	rootCmd.AddCommand(relicenseCmd)
+}
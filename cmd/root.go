@@ -5,17 +5,41 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/palantir/go-license/commoncmd"
 	"github.com/palantir/go-license/golicense"
+	"github.com/palantir/go-license/golicense/gitrev"
 	"github.com/palantir/pkg/cobracli"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 var (
 	rootCmd = &cobra.Command{
-		Use:   "go-license [flags] [files]",
-		Short: "Write or verify license headers for Go files",
+		Use:   "go-license [flags] [files|@file-list]",
+		Short: "Write or verify license headers for Go files and other configured file types",
+		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if stdinFlagVal {
+				if filenameFlagVal == "" {
+					return errors.Errorf("--filename must be specified when --stdin is used")
+				}
+				if len(args) > 0 || filesFromFlagVal != "" || revFlagVal != "" {
+					return errors.Errorf("files cannot be specified when --stdin is used")
+				}
+			}
+			if anyKnownFlagVal && (!removeFlagVal || verifyFlagVal || stdinFlagVal || revFlagVal != "") {
+				return errors.Errorf("--any-known can only be used with --remove")
+			}
+			args, err := commoncmd.ResolveFiles(args, filesFromFlagVal, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			if revFlagVal != "" {
+				return runVerifyRevision(args, cmd.OutOrStdout())
+			}
 			projectCfg, err := commoncmd.LoadConfig(cfgFlagVal)
 			if err != nil {
 				return err
@@ -24,21 +48,84 @@ var (
 			if err != nil {
 				return err
 			}
+			applyParamFlags(&projectParam)
+			if stdinFlagVal {
+				return golicense.RunLicenseStdin(filenameFlagVal, projectParam, verifyFlagVal, removeFlagVal, cmd.InOrStdin(), cmd.OutOrStdout())
+			}
+			if anyKnownFlagVal {
+				return golicense.RunUnlicenseAnyKnown(args, projectParam, cmd.OutOrStdout())
+			}
 			return golicense.RunLicense(args, projectParam, verifyFlagVal, removeFlagVal, cmd.OutOrStdout())
 		},
 	}
 
-	cfgFlagVal    string
-	verifyFlagVal bool
-	removeFlagVal bool
+	cfgFlagVal          string
+	verifyFlagVal       bool
+	removeFlagVal       bool
+	anyKnownFlagVal     bool
+	revFlagVal          string
+	filesFromFlagVal    string
+	stdinFlagVal        bool
+	filenameFlagVal     string
+	convertStyleFlagVal bool
+	parseCheckFlagVal   bool
+	strictFlagVal       bool
 )
 
+func runVerifyRevision(files []string, stdout io.Writer) error {
+	if !verifyFlagVal {
+		return errors.Errorf("--rev can only be used with --verify")
+	}
+	fsys, err := gitrev.New(".", revFlagVal)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = fsys.Close()
+	}()
+	projectCfg, err := commoncmd.LoadConfigAtRevision(cfgFlagVal, fsys)
+	if err != nil {
+		return err
+	}
+	projectParam, err := projectCfg.ToParam()
+	if err != nil {
+		return err
+	}
+	applyParamFlags(&projectParam)
+	if len(files) == 0 && filesFromFlagVal == "" {
+		if files, err = fsys.Files(); err != nil {
+			return err
+		}
+	}
+	if ok, err := golicense.VerifyFS(fsys, files, projectParam, stdout); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("")
+	}
+	return nil
+}
+
+// applyParamFlags sets the parameters of projectParam that are specified by flags rather than by the configuration.
+func applyParamFlags(projectParam *golicense.ProjectParam) {
+	projectParam.ConvertCommentStyle = convertStyleFlagVal
+	projectParam.ParseCheck = parseCheckFlagVal
+	projectParam.Strict = strictFlagVal
+}
+
 func Execute() int {
 	return cobracli.ExecuteWithDefaultParams(rootCmd)
 }
 
 func init() {
-	rootCmd.Flags().StringVar(&cfgFlagVal, "config", "", "the YAML configuration file for the license check")
+	rootCmd.PersistentFlags().StringVar(&cfgFlagVal, "config", "", "the YAML configuration file for the license check")
 	rootCmd.Flags().BoolVar(&verifyFlagVal, "verify", false, "verify that files have proper license headers applied")
 	rootCmd.Flags().BoolVar(&removeFlagVal, "remove", false, "remove the license header from files (no-op if verify is true)")
+	rootCmd.Flags().BoolVar(&anyKnownFlagVal, "any-known", false, "when removing, remove the default header or any custom header rather than only the header that applies to each file, and print the header removed from each file (requires remove)")
+	rootCmd.Flags().BoolVar(&convertStyleFlagVal, "convert-style", false, `convert headers rendered in the other comment style ("//" or "/* */") to the configured style (when verifying, report such headers as incorrect)`)
+	rootCmd.Flags().BoolVar(&parseCheckFlagVal, "parse-check", false, "check that modified Go files still parse and have the same package clause, build constraints and //go: directives, leaving files that fail the check unmodified")
+	rootCmd.Flags().BoolVar(&strictFlagVal, "strict", false, "match only the canonical header (when adding licenses, replace accepted-headers with the canonical header, and when verifying, report them as incorrect)")
+	rootCmd.Flags().StringVar(&filesFromFlagVal, "files-from", "", `read the files to process from the specified file ("-" for stdin) in addition to the files specified as arguments (entries may be separated by newlines or NUL characters)`)
+	rootCmd.Flags().BoolVar(&stdinFlagVal, "stdin", false, "read the content of a single file from stdin and write the result to stdout rather than processing files on disk (requires filename)")
+	rootCmd.Flags().StringVar(&filenameFlagVal, "filename", "", "the path of the file whose content is provided on stdin, used to determine the header that applies to it")
+	rootCmd.Flags().StringVar(&revFlagVal, "rev", "", "verify the files in the tree of the specified git revision rather than the working tree using the configuration at that revision (requires verify; if no files are specified, all files in the revision are verified)")
 }
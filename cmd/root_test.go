@@ -0,0 +1,108 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootCmdFileArgs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const cfgFile, goFile = "license.yml", "foo.go"
+	require.NoError(t, os.WriteFile(cfgFile, []byte("header: // Copyright 2016 Foo Co.\n"), 0644))
+	require.NoError(t, os.WriteFile(goFile, []byte("package foo\n"), 0644))
+
+	// the root command has a subcommand, so its positional arguments must still be accepted as files
+	for _, args := range [][]string{
+		{"--config=" + cfgFile, goFile},
+		{"--config=" + cfgFile, "--verify", goFile},
+	} {
+		out, err := executeRootCmd(args...)
+		require.NoError(t, err, "args: %v, output: %s", args, out)
+	}
+
+	got, err := os.ReadFile(goFile)
+	require.NoError(t, err)
+	assert.Equal(t, "// Copyright 2016 Foo Co.\n\npackage foo\n", string(got))
+}
+
+func TestRootCmdRevFlags(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const cfgFile = "license.yml"
+	require.NoError(t, os.WriteFile(cfgFile, []byte(`header: // Copyright 2016 Foo Co.
+accepted-headers:
+  - // Copyright 2016 Bar Co.
+`), 0644))
+	require.NoError(t, os.WriteFile("foo.go", []byte("// Copyright 2016 Bar Co.\n\npackage foo\n"), 0644))
+	for _, args := range [][]string{
+		{"init"},
+		{"add", "."},
+		{"commit", "-m", "first"},
+	} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, string(out))
+	}
+
+	out, err := executeRootCmd("--config="+cfgFile, "--verify", "--rev=HEAD")
+	require.NoError(t, err, out)
+
+	// flags that affect verification apply to the revision as well
+	out, err = executeRootCmd("--config="+cfgFile, "--verify", "--strict", "--rev=HEAD")
+	require.Error(t, err)
+	assert.Contains(t, out, "1 file does not have the correct license header:\n\tfoo.go (accepted license header that is not the canonical header)\n")
+}
+
+func TestRootCmdRevConfigOutsideWorkingDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Chdir(tmpDir)
+	require.NoError(t, os.WriteFile("license.yml", []byte("header: // Copyright 2016 Foo Co.\n"), 0644))
+	require.NoError(t, os.Mkdir("sub", 0755))
+	require.NoError(t, os.WriteFile(filepath.Join("sub", "foo.go"), []byte("package foo\n"), 0644))
+	for _, args := range [][]string{
+		{"init"},
+		{"add", "."},
+		{"commit", "-m", "first"},
+	} {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, string(out))
+	}
+
+	// the configuration file is read from the revision even if it is above the working directory
+	t.Chdir("sub")
+	for _, cfgFile := range []string{
+		filepath.Join("..", "license.yml"),
+		filepath.Join(tmpDir, "license.yml"),
+	} {
+		out, err := executeRootCmd("--config="+cfgFile, "--verify", "--rev=HEAD")
+		require.Error(t, err, "config: %s", cfgFile)
+		assert.Contains(t, out, "1 file does not have the correct license header:\n\tfoo.go (no license header)\n", "config: %s", cfgFile)
+	}
+}
+
+// executeRootCmd executes the root command with the provided arguments after resetting its flags to their defaults
+// and returns its output.
+func executeRootCmd(args ...string) (string, error) {
+	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+	out := &bytes.Buffer{}
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(out)
+	rootCmd.SetErr(io.Discard)
+	err := rootCmd.Execute()
+	return out.String(), err
+}
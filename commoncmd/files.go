@@ -0,0 +1,92 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package commoncmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ResolveFiles returns the files that should be processed given the positional arguments and the value of the
+// "--files-from" flag. Any argument of the form "@<path>" is replaced by the files listed in the file at <path>. If
+// filesFrom is non-empty, the files listed in it are appended: a value of "-" reads the list from stdin. Lists may be
+// separated by newlines or by NUL characters (as produced by "git ls-files -z" or "find -print0").
+func ResolveFiles(args []string, filesFrom string, stdin io.Reader) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") {
+			files = append(files, arg)
+			continue
+		}
+		listFiles, err := readFileListFromPath(strings.TrimPrefix(arg, "@"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, listFiles...)
+	}
+
+	switch filesFrom {
+	case "":
+	case "-":
+		listFiles, err := ReadFileList(stdin)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file list from stdin")
+		}
+		files = append(files, listFiles...)
+	default:
+		listFiles, err := readFileListFromPath(filesFrom)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, listFiles...)
+	}
+	return files, nil
+}
+
+// ReadFileList reads a list of file paths from the provided reader. If the content contains a NUL character, paths
+// are separated by NUL characters; otherwise, they are separated by newlines. Empty entries are ignored.
+func ReadFileList(r io.Reader) ([]string, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var entries []string
+	if bytes.IndexByte(content, 0) >= 0 {
+		entries = strings.Split(string(content), "\x00")
+	} else {
+		entries = strings.Split(string(content), "\n")
+		for i := range entries {
+			entries[i] = strings.TrimSuffix(entries[i], "\r")
+		}
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry != "" {
+			files = append(files, entry)
+		}
+	}
+	return files, nil
+}
+
+func readFileListFromPath(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open file list %s", path)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	files, err := ReadFileList(f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file list %s", path)
+	}
+	return files, nil
+}
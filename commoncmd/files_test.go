@@ -0,0 +1,74 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package commoncmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/palantir/go-license/commoncmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	newlineList := filepath.Join(tmpDir, "newline.txt")
+	err := os.WriteFile(newlineList, []byte("a.go\r\nb b.go\n\nc.go\n"), 0644)
+	require.NoError(t, err)
+	nulList := filepath.Join(tmpDir, "nul.txt")
+	err = os.WriteFile(nulList, []byte("d.go\x00e\ne.go\x00"), 0644)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name      string
+		args      []string
+		filesFrom string
+		stdin     string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name: "plain arguments",
+			args: []string{"a.go", "b.go"},
+			want: []string{"a.go", "b.go"},
+		},
+		{
+			name: "argument files are expanded in place",
+			args: []string{"x.go", "@" + newlineList, "y.go", "@" + nulList},
+			want: []string{"x.go", "a.go", "b b.go", "c.go", "y.go", "d.go", "e\ne.go"},
+		},
+		{
+			name:      "files from stdin are appended",
+			args:      []string{"x.go"},
+			filesFrom: "-",
+			stdin:     "a.go\x00b.go\x00",
+			want:      []string{"x.go", "a.go", "b.go"},
+		},
+		{
+			name:      "files from file are appended",
+			filesFrom: newlineList,
+			want:      []string{"a.go", "b b.go", "c.go"},
+		},
+		{
+			name:    "missing argument file is an error",
+			args:    []string{"@" + filepath.Join(tmpDir, "missing.txt")},
+			wantErr: "failed to open file list " + filepath.Join(tmpDir, "missing.txt"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := commoncmd.ResolveFiles(tc.args, tc.filesFrom, strings.NewReader(tc.stdin))
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package commoncmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/palantir/go-license/golicense/config"
+	"github.com/pkg/errors"
+)
+
+// DefaultHeaderName is the header reference that refers to the default header of the configuration.
+const DefaultHeaderName = "default"
+
+// ResolveHeader returns the Licenser for the provided header reference. The reference is either DefaultHeaderName (the
+// default header of projectParam), the name of a custom header of projectParam or the path of a file whose content is
+// the header (a "//" or "/* */" comment, without its final newline). Names take precedence over paths.
+func ResolveHeader(ref string, projectParam golicense.ProjectParam) (golicense.Licenser, error) {
+	if ref == DefaultHeaderName {
+		if projectParam.Licenser == nil || projectParam.Licenser.Empty() {
+			return nil, errors.Errorf("configuration does not specify a default header")
+		}
+		return projectParam.Licenser, nil
+	}
+	for _, customHeader := range projectParam.CustomHeaders {
+		if customHeader.Name == ref {
+			return customHeader.Licenser, nil
+		}
+	}
+
+	headerBytes, err := os.ReadFile(ref)
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("%s is not %q, the name of a custom header or a header file", ref, DefaultHeaderName)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read header file %s", ref)
+	}
+	header := strings.TrimSuffix(strings.TrimSuffix(string(headerBytes), "\n"), "\r")
+	if header == "" {
+		return nil, errors.Errorf("header file %s is empty", ref)
+	}
+	if err := config.ValidateHeader(header); err != nil {
+		return nil, errors.Wrapf(err, "invalid header file %s", ref)
+	}
+	return golicense.NewLicenser(header), nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package commoncmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/go-license/commoncmd"
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveHeader(t *testing.T) {
+	tmpDir := t.TempDir()
+	headerFile := filepath.Join(tmpDir, "header.txt")
+	err := os.WriteFile(headerFile, []byte("// Copyright {{YEAR}} File Co.\n"), 0644)
+	require.NoError(t, err)
+	invalidHeaderFile := filepath.Join(tmpDir, "invalid.txt")
+	err = os.WriteFile(invalidHeaderFile, []byte("Copyright {{YEAR}} File Co.\n"), 0644)
+	require.NoError(t, err)
+
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser("// Copyright {{YEAR}} Default Co."),
+		CustomHeaders: []golicense.CustomHeaderParam{
+			{
+				Name:     "custom",
+				Licenser: golicense.NewLicenser("// Copyright {{YEAR}} Custom Co."),
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		ref          string
+		projectParam golicense.ProjectParam
+		wantMatch    string
+		wantErr      string
+	}{
+		{
+			name:         "default header",
+			ref:          "default",
+			projectParam: projectParam,
+			wantMatch:    "// Copyright 2016 Default Co.\n",
+		},
+		{
+			name:         "custom header",
+			ref:          "custom",
+			projectParam: projectParam,
+			wantMatch:    "// Copyright 2016 Custom Co.\n",
+		},
+		{
+			name:         "header file",
+			ref:          headerFile,
+			projectParam: projectParam,
+			wantMatch:    "// Copyright 2016 File Co.\n\npackage foo\n",
+		},
+		{
+			name:         "invalid header file",
+			ref:          invalidHeaderFile,
+			projectParam: projectParam,
+			wantErr:      `invalid header file ` + invalidHeaderFile + `: header is not a valid Go comment: line is not a comment: "Copyright {{YEAR}} File Co."`,
+		},
+		{
+			name:    "no default header",
+			ref:     "default",
+			wantErr: "configuration does not specify a default header",
+		},
+		{
+			name:         "unknown reference",
+			ref:          "unknown",
+			projectParam: projectParam,
+			wantErr:      `unknown is not "default", the name of a custom header or a header file`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			licenser, err := commoncmd.ResolveHeader(tc.ref, tc.projectParam)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, licenser.Matches(tc.wantMatch))
+		})
+	}
+}
@@ -5,9 +5,13 @@
 package commoncmd
 
 import (
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/palantir/go-license/golicense/config"
+	"github.com/palantir/go-license/golicense/gitrev"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -21,10 +25,71 @@ func LoadConfig(cfgFile string) (config.ProjectConfig, error) {
 		return config.ProjectConfig{}, errors.Wrapf(err, "failed to read file %s", cfgFile)
 	}
 
-	upgradedBytes, err := config.UpgradeConfig(cfgYML)
+	cfg, err := unmarshalConfig(cfgYML)
 	if err != nil {
 		return config.ProjectConfig{}, errors.Wrapf(err, "failed to read file %s", cfgFile)
 	}
+	if err := validateHeaders(cfgFile, cfgYML, cfg); err != nil {
+		return config.ProjectConfig{}, err
+	}
+	return cfg, nil
+}
+
+// LoadConfigAtRevision loads the configuration file as it exists in the tree of the revision provided by fsys. The
+// configuration file path is interpreted relative to the working directory and may refer to any file in the git
+// repository, including files outside of the directory of fsys. If the file does not exist in the revision, an empty
+// configuration is returned.
+func LoadConfigAtRevision(cfgFile string, fsys *gitrev.FS) (config.ProjectConfig, error) {
+	if cfgFile == "" {
+		return config.ProjectConfig{}, nil
+	}
+	repoPath, err := repoRelativePath(cfgFile, fsys.TopLevel())
+	if err != nil {
+		return config.ProjectConfig{}, err
+	}
+	cfgYML, err := fsys.ReadRepoFile(repoPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config.ProjectConfig{}, nil
+	}
+	if err != nil {
+		return config.ProjectConfig{}, errors.Wrapf(err, "failed to read file %s", cfgFile)
+	}
+
+	cfg, err := unmarshalConfig(cfgYML)
+	if err != nil {
+		return config.ProjectConfig{}, errors.Wrapf(err, "failed to read file %s", cfgFile)
+	}
+	if err := validateHeaders(cfgFile, cfgYML, cfg); err != nil {
+		return config.ProjectConfig{}, err
+	}
+	return cfg, nil
+}
+
+// repoRelativePath returns the slash-separated path of the provided file relative to the root of the git repository.
+// Symbolic links in the directory of the file are resolved because the root reported by git is always resolved.
+func repoRelativePath(file, topLevel string) (string, error) {
+	absPath, err := filepath.Abs(file)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to determine absolute path of %s", file)
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(absPath)); err == nil {
+		absPath = filepath.Join(dir, filepath.Base(absPath))
+	}
+	if resolved, err := filepath.EvalSymlinks(topLevel); err == nil {
+		topLevel = resolved
+	}
+	relPath, err := filepath.Rel(topLevel, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("%s is not in the git repository %s", file, topLevel)
+	}
+	return filepath.ToSlash(relPath), nil
+}
+
+func unmarshalConfig(cfgYML []byte) (config.ProjectConfig, error) {
+	upgradedBytes, err := config.UpgradeConfig(cfgYML)
+	if err != nil {
+		return config.ProjectConfig{}, err
+	}
 
 	var cfg config.ProjectConfig
 	if err := yaml.Unmarshal(upgradedBytes, &cfg); err != nil {
@@ -32,3 +97,48 @@ func LoadConfig(cfgFile string) (config.ProjectConfig, error) {
 	}
 	return cfg, nil
 }
+
+// validateHeaders returns an error if a header in the provided configuration is not a valid Go comment. The error
+// identifies the line of the configuration file that contains the invalid line of the header if it can be found.
+// Other configuration errors are reported when the configuration is converted to parameters.
+func validateHeaders(cfgFile string, cfgYML []byte, cfg config.ProjectConfig) error {
+	err := cfg.ValidateHeaders()
+	headerErr, ok := errors.Cause(err).(*config.HeaderError)
+	if !ok {
+		return nil
+	}
+	if line := headerErrorLine(cfgYML, headerErr); line > 0 {
+		return errors.Wrapf(err, "%s:%d", cfgFile, line)
+	}
+	return errors.Wrapf(err, "%s", cfgFile)
+}
+
+// headerErrorLine returns the 1-based line of the provided configuration YAML that contains the invalid line of the
+// header of the provided error. Returns 0 if the line cannot be found (for example, if the header is specified as a
+// quoted string).
+func headerErrorLine(cfgYML []byte, headerErr *config.HeaderError) int {
+	text := strings.TrimSpace(headerErr.Line)
+	if text == "" {
+		return 0
+	}
+	lines := strings.Split(string(cfgYML), "\n")
+	start := 0
+	if headerErr.CustomHeader != "" {
+		// start at the entry of the custom header so that a line with the same content in another header is not found
+		for i, line := range lines {
+			if name, ok := strings.CutPrefix(strings.TrimLeft(strings.TrimSpace(line), "- "), "name:"); ok && strings.Trim(strings.TrimSpace(name), `"'`) == headerErr.CustomHeader {
+				start = i
+				break
+			}
+		}
+	}
+	// the entry may specify its header before its name, so fall back to searching the whole file
+	for _, from := range []int{start, 0} {
+		for i := from; i < len(lines); i++ {
+			if line := strings.TrimSpace(lines[i]); line == text || strings.HasSuffix(line, ": "+text) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package commoncmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/go-license/commoncmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigInvalidHeader(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		yml     string
+		wantErr string
+	}{
+		{
+			name: "valid headers",
+			yml: `header: |
+  // Copyright {{YEAR}} Palantir Technologies, Inc.
+  /* Block comment */
+custom-headers:
+  - name: foo
+    header: Copyright 2016 Foo Co.
+    header-style: block
+    paths:
+      - foo
+`,
+		},
+		{
+			name: "header line without comment prefix",
+			yml: `header: |
+  // Copyright {{YEAR}} Palantir Technologies, Inc.
+  Licensed under the Apache License.
+`,
+			wantErr: `license.yml:3: header is not a valid Go comment: line is not a comment: "Licensed under the Apache License."`,
+		},
+		{
+			name: "unterminated block comment",
+			yml: `header: |
+  /* Copyright {{YEAR}} Palantir Technologies, Inc.
+`,
+			wantErr: `license.yml:2: header is not a valid Go comment: comment not terminated: "/* Copyright {{YEAR}} Palantir Technologies, Inc."`,
+		},
+		{
+			name: "custom header line found in its entry",
+			yml: `header: |
+  // Copyright 2016 Palantir Technologies, Inc.
+  // Foo
+custom-headers:
+  - name: bar
+    header: |
+      // Copyright 2016 Bar Co.
+      // Foo
+    paths:
+      - bar
+  - name: foo
+    paths:
+      - foo
+    header: |
+      // Copyright 2016 Foo Co.
+      Foo
+`,
+			wantErr: `license.yml:16: invalid configuration for custom header foo: header is not a valid Go comment: line is not a comment: "Foo"`,
+		},
+		{
+			name: "block comment closed in block header style",
+			yml: `header: |
+  Copyright 2016 Palantir Technologies, Inc. */ var x
+header-style: block
+`,
+			wantErr: `license.yml:2: header is not a valid Go comment: line is not a comment: "Copyright 2016 Palantir Technologies, Inc. */ var x"`,
+		},
+		{
+			name:    "line not found in quoted header",
+			yml:     `header: "// Copyright 2016 Palantir Technologies, Inc.\nFoo\n"`,
+			wantErr: `license.yml: header is not a valid Go comment: line is not a comment: "Foo"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfgFile := filepath.Join(t.TempDir(), "license.yml")
+			err := os.WriteFile(cfgFile, []byte(tc.yml), 0644)
+			require.NoError(t, err)
+
+			_, err = commoncmd.LoadConfig(cfgFile)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, filepath.Dir(cfgFile)+string(filepath.Separator)+tc.wantErr)
+			}
+		})
+	}
+}
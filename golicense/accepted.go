@@ -0,0 +1,59 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+// NewLicenserWithAccepted returns a Licenser for the provided license header (see NewLicenser) that also accepts the
+// provided headers in its place. Accepted headers are matched and removed in the same manner as the license, which is
+// useful while a project transitions from one header to another (for example, during a relicensing or after the
+// copyright holder is renamed), but the license is always added using the provided license header.
+func NewLicenserWithAccepted(license string, accepted ...string) Licenser {
+	l := NewLicenser(license).(*licenserImpl)
+	for _, header := range accepted {
+		l.accepted = append(l.accepted, NewLicenser(header).(*licenserImpl))
+	}
+	return l
+}
+
+// strictSetter is implemented by Licensers that accept headers other than their own.
+type strictSetter interface {
+	// withStrict returns a Licenser that matches only its own header and that replaces an accepted header with its
+	// own header when adding the license.
+	withStrict() Licenser
+}
+
+// acceptedMatcher is implemented by Licensers that accept headers other than their own.
+type acceptedMatcher interface {
+	// matchesAccepted returns true if the provided content starts with one of the accepted headers of the Licenser.
+	matchesAccepted(content string) bool
+}
+
+func (l *licenserImpl) withStrict() Licenser {
+	strict := *l
+	strict.strict = true
+	return &strict
+}
+
+func (l *licenserImpl) matchesAccepted(content string) bool {
+	_, content = splitProlog(content)
+	_, ok := l.acceptedEnd(content[l.licenseStart(content):], !l.convert)
+	return ok
+}
+
+// acceptedEnd is like headerEnd, but for the accepted headers of the license. If alternates is true, the alternate
+// renderings of the accepted headers are also considered.
+func (l *licenserImpl) acceptedEnd(content string, alternates bool) (int, bool) {
+	for _, accepted := range l.accepted {
+		if end, ok := accepted.headerEnd(content); ok {
+			return end, true
+		}
+		if !alternates {
+			continue
+		}
+		if end, ok := accepted.alternateEnd(content); ok {
+			return end, true
+		}
+	}
+	return 0, false
+}
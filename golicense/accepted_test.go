@@ -0,0 +1,116 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptedHeaders(t *testing.T) {
+	newHeader := fmt.Sprintf("// Copyright %d New Co.\n\n", time.Now().Year())
+	licenser := golicense.NewLicenserWithAccepted("// Copyright {{YEAR}} New Co.", "// Copyright {{YEAR}} Old Co.\n// All rights reserved.")
+
+	for _, tc := range []struct {
+		name        string
+		strict      bool
+		thirdParty  golicense.ThirdPartyPolicy
+		content     string
+		wantMatch   bool
+		wantContent string
+		wantRemoved string
+	}{
+		{
+			name:        "canonical header matches",
+			content:     "// Copyright 2016 New Co.\n\npackage foo\n",
+			wantMatch:   true,
+			wantContent: "// Copyright 2016 New Co.\n\npackage foo\n",
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:        "accepted header matches",
+			content:     "// Copyright 2015 Old Co.\n// All rights reserved.\n\npackage foo\n",
+			wantMatch:   true,
+			wantContent: "// Copyright 2015 Old Co.\n// All rights reserved.\n\npackage foo\n",
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:        "accepted header in other comment style matches",
+			content:     "/*\nCopyright 2015 Old Co.\nAll rights reserved.\n*/\n\npackage foo\n",
+			wantMatch:   true,
+			wantContent: "/*\nCopyright 2015 Old Co.\nAll rights reserved.\n*/\n\npackage foo\n",
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:        "accepted header replaced when strict",
+			strict:      true,
+			content:     "// Copyright 2015 Old Co.\n// All rights reserved.\n\npackage foo\n",
+			wantContent: newHeader + "package foo\n",
+			wantRemoved: "// Copyright 2015 Old Co.\n// All rights reserved.\n\npackage foo\n",
+		},
+		{
+			name:        "canonical header matches when strict",
+			strict:      true,
+			content:     "// Copyright 2016 New Co.\n\npackage foo\n",
+			wantMatch:   true,
+			wantContent: "// Copyright 2016 New Co.\n\npackage foo\n",
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:        "accepted header is not third-party code",
+			thirdParty:  golicense.ThirdPartyFail,
+			content:     "// Copyright 2015 Old Co.\n// All rights reserved.\n\npackage foo\n",
+			wantMatch:   true,
+			wantContent: "// Copyright 2015 Old Co.\n// All rights reserved.\n\npackage foo\n",
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:        "header added above other content",
+			content:     "package foo\n",
+			wantContent: newHeader + "package foo\n",
+			wantRemoved: "package foo\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam := golicense.ProjectParam{
+				Licenser:         licenser,
+				ThirdPartyPolicy: tc.thirdParty,
+				Strict:           tc.strict,
+			}
+
+			buf := &bytes.Buffer{}
+			ok, err := golicense.VerifyFS(fstest.MapFS{"foo.go": &fstest.MapFile{Data: []byte(tc.content)}}, []string{"foo.go"}, projectParam, buf)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMatch, ok, buf.String())
+
+			got, _ := golicense.LicenseContent("foo.go", []byte(tc.content), projectParam)
+			assert.Equal(t, tc.wantContent, string(got))
+
+			removed, _ := golicense.UnlicenseContent("foo.go", []byte(tc.content), projectParam)
+			assert.Equal(t, tc.wantRemoved, string(removed))
+		})
+	}
+
+	t.Run("strict verification output", func(t *testing.T) {
+		projectParam := golicense.ProjectParam{
+			Licenser: licenser,
+			Strict:   true,
+		}
+		buf := &bytes.Buffer{}
+		ok, err := golicense.VerifyFS(fstest.MapFS{
+			"foo.go": &fstest.MapFile{Data: []byte("// Copyright 2015 Old Co.\n// All rights reserved.\n\npackage foo\n")},
+		}, []string{"foo.go"}, projectParam, buf)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "1 file does not have the correct license header:\n\tfoo.go (accepted license header that is not the canonical header)\n", buf.String())
+	})
+}
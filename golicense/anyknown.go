@@ -0,0 +1,125 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// RemovedHeader identifies the header that was removed from a file.
+type RemovedHeader struct {
+	// Path is the path of the file.
+	Path string
+	// HeaderName is the name of the custom header that was removed, or empty if the default header was removed.
+	HeaderName string
+}
+
+func (r RemovedHeader) String() string {
+	if r.HeaderName == "" {
+		return fmt.Sprintf("removed default header from %s", r.Path)
+	}
+	return fmt.Sprintf("removed custom header %s from %s", r.HeaderName, r.Path)
+}
+
+// RunUnlicenseAnyKnown removes any known header from the provided files using UnlicenseAnyKnownFiles and prints the
+// header that was removed from each file to stdout.
+func RunUnlicenseAnyKnown(files []string, projectParam ProjectParam, stdout io.Writer) error {
+	removed, err := UnlicenseAnyKnownFiles(files, projectParam)
+	for _, r := range removed {
+		_, _ = fmt.Fprintln(stdout, r)
+	}
+	return err
+}
+
+func UnlicenseAnyKnownFiles(files []string, projectParam ProjectParam) ([]RemovedHeader, error) {
+	return UnlicenseAnyKnownFilesContext(context.Background(), files, projectParam)
+}
+
+// UnlicenseAnyKnownFilesContext is like UnlicenseFilesContext, but removes any known header rather than only the
+// header that applies to each file based on its path. The known headers are the default header and the headers of all
+// of the custom headers: the header that applies to the file is tried first, followed by the default header and the
+// custom headers in order, and the first one that the file starts with is removed. Returns the header that was removed
+// from each modified file in the order in which the files were provided. If the context is done before all of the
+// files are processed, the headers removed so far are returned along with the error of the context.
+func UnlicenseAnyKnownFilesContext(ctx context.Context, files []string, projectParam ProjectParam) ([]RemovedHeader, error) {
+	var removed []RemovedHeader
+	failures := newParseCheckFailures(projectParam.ParseCheck)
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		if !isIncluded(f, projectParam) {
+			projectParam.Observer.notify(Event{Type: EventFileExcluded, Path: f})
+			continue
+		}
+		headerName := ""
+		if customHeader, ok := customHeaderForFile(f, projectParam.CustomHeaders); ok {
+			headerName = customHeader.Name
+		}
+		projectParam.Observer.notify(Event{Type: EventFileSelected, Path: f, HeaderName: headerName})
+
+		observer := projectParam.Observer.withHeaderName(headerName)
+		if _, err := visitFiles(ctx, []string{f}, observer, func(path string, fi os.FileInfo, content string) (bool, bool, error) {
+			name, newContent, ok := removeAnyKnownLicense(path, content, headerName, projectParam)
+			if !ok {
+				return false, false, nil
+			}
+			written, err := writeModifiedFile(path, content, newContent, fi.Mode(), failures, observer)
+			if err != nil {
+				return true, false, errors.Wrapf(err, "failed to write file %s with license removed", path)
+			}
+			if written {
+				removed = append(removed, RemovedHeader{Path: path, HeaderName: name})
+			}
+			return true, written, nil
+		}); err != nil {
+			return removed, err
+		}
+	}
+	return removed, failures.err()
+}
+
+// removeAnyKnownLicense returns the name of the first known header that the provided content of the file at the
+// provided path starts with and the content with that header removed, trying the header with the provided name (the
+// header that applies to the file) first. Returns false if the content does not start with any known header.
+func removeAnyKnownLicense(path, content, headerName string, projectParam ProjectParam) (string, string, bool) {
+	type knownHeader struct {
+		name     string
+		licenser Licenser
+	}
+	known := []knownHeader{{licenser: projectParam.Licenser}}
+	if projectParam.Licenser != nil {
+		known[0].licenser = projectParam.configuredLicenser()
+	}
+	for _, customHeader := range projectParam.CustomHeaders {
+		h := knownHeader{name: customHeader.Name, licenser: customHeader.Licenser}
+		if h.licenser != nil {
+			h.licenser = customHeader.configuredLicenser()
+		}
+		if h.name == headerName {
+			known = append([]knownHeader{h}, known...)
+		} else {
+			known = append(known, h)
+		}
+	}
+
+	fileType, _ := fileTypeForFile(path, projectParam)
+	for _, h := range known {
+		if h.licenser == nil || h.licenser.Empty() {
+			continue
+		}
+		licenser := licenserForFileType(h.licenser, fileType, projectParam)
+		// content that matches only because it is skipped third-party code is left unmodified by Remove
+		if newContent, ok := removeLicense(licenser, content); ok && newContent != content {
+			return h.name, newContent, true
+		}
+	}
+	return "", content, false
+}
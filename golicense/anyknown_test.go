@@ -0,0 +1,80 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/palantir/pkg/matcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlicenseAnyKnownFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd := chdir(t, tmpDir)
+	defer oldWd()
+
+	writeFiles(t, tmpDir, map[string]string{
+		"default.go":          "// Copyright 2016 Default Co.\n\npackage foo\n",
+		"excluded.go":         "// Copyright 2016 Default Co.\n\npackage foo\n",
+		"none.go":             "package foo\n",
+		"bar/bar.go":          "// Copyright 2016 Bar Co.\n\npackage bar\n",
+		"foo/default.go":      "// Copyright 2016 Default Co.\n\npackage foo\n",
+		"foo/foo.go":          "// Copyright 2016 Foo Co.\n\npackage foo\n",
+		"foo/bar.go":          "/*\nCopyright 2016 Bar Co.\n*/\n\npackage foo\n",
+		"foo/thirdparty.go":   "// Copyright 2009 The Go Authors.\n\npackage foo\n",
+		"foo/both_headers.go": "// Copyright 2016 Default Co.\n\n// Copyright 2016 Foo Co.\n\npackage foo\n",
+	})
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser("// Copyright {{YEAR}} Default Co."),
+		Exclude:  matcher.Name("excluded.go"),
+		CustomHeaders: []golicense.CustomHeaderParam{
+			{
+				Name:         "foo",
+				Licenser:     golicense.NewLicenser("// Copyright {{YEAR}} Foo Co."),
+				IncludePaths: []string{"foo"},
+			},
+			{
+				Name:             "bar",
+				Licenser:         golicense.NewLicenser("// Copyright {{YEAR}} Bar Co."),
+				ThirdPartyPolicy: golicense.ThirdPartySkip,
+				IncludePaths:     []string{"bar"},
+			},
+		},
+	}
+
+	files := []string{"bar/bar.go", "default.go", "excluded.go", "foo/bar.go", "foo/both_headers.go", "foo/default.go", "foo/foo.go", "foo/thirdparty.go", "none.go"}
+	buf := &bytes.Buffer{}
+	err := golicense.RunUnlicenseAnyKnown(files, projectParam, buf)
+	require.NoError(t, err)
+	assert.Equal(t, `removed custom header bar from bar/bar.go
+removed default header from default.go
+removed custom header bar from foo/bar.go
+removed default header from foo/both_headers.go
+removed default header from foo/default.go
+removed custom header foo from foo/foo.go
+`, buf.String())
+
+	for path, want := range map[string]string{
+		"bar/bar.go":          "package bar\n",
+		"default.go":          "package foo\n",
+		"excluded.go":         "// Copyright 2016 Default Co.\n\npackage foo\n",
+		"foo/bar.go":          "package foo\n",
+		"foo/both_headers.go": "// Copyright 2016 Foo Co.\n\npackage foo\n",
+		"foo/default.go":      "package foo\n",
+		"foo/foo.go":          "package foo\n",
+		"foo/thirdparty.go":   "// Copyright 2009 The Go Authors.\n\npackage foo\n",
+		"none.go":             "package foo\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(tmpDir, path))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content), path)
+	}
+}
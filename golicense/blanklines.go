@@ -0,0 +1,127 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"fmt"
+	"strings"
+)
+
+// blankLinesSetter is implemented by Licensers that support a policy for the number of blank lines after their license.
+type blankLinesSetter interface {
+	// withBlankLinesAfter returns a Licenser for the same header that enforces the provided number of blank lines
+	// between the license and the rest of the content.
+	withBlankLinesAfter(n int) Licenser
+}
+
+// blankLinesChecker is implemented by Licensers that enforce the number of blank lines after their license.
+type blankLinesChecker interface {
+	// blankLines returns the number of blank lines after the license in the provided content and the number that is
+	// enforced. Returns false if the Licenser does not enforce a number of blank lines, the content does not have the
+	// license or the license is not followed by any other content.
+	blankLines(content string) (int, int, bool)
+	// normalizeBlankLines returns the provided content with the enforced number of blank lines after the license.
+	// Content that does not have the license is returned unmodified.
+	normalizeBlankLines(content string) string
+}
+
+// licenserForBlankLines returns a Licenser for the header of the provided Licenser that enforces the provided number of
+// blank lines after the license. If n is 0, the number of blank lines is not enforced and the provided Licenser is
+// returned unmodified, as are Licensers that do not support enforcing the number of blank lines.
+func licenserForBlankLines(licenser Licenser, n int) Licenser {
+	if n <= 0 || licenser.Empty() {
+		return licenser
+	}
+	if s, ok := licenser.(blankLinesSetter); ok {
+		return s.withBlankLinesAfter(n)
+	}
+	return licenser
+}
+
+// normalizeBlankLines returns the provided content with the number of blank lines after the license of the provided
+// Licenser enforced if the Licenser enforces it.
+func normalizeBlankLines(licenser Licenser, content string) string {
+	if c, ok := licenser.(blankLinesChecker); ok {
+		return c.normalizeBlankLines(content)
+	}
+	return content
+}
+
+func (l *licenserImpl) withBlankLinesAfter(n int) Licenser {
+	withBlankLines := *l
+	withBlankLines.blankLinesAfter = n
+	return &withBlankLines
+}
+
+func (l *licenserImpl) blankLines(content string) (int, int, bool) {
+	if l.blankLinesAfter == 0 {
+		return 0, 0, false
+	}
+	_, count, restStart, ok := l.blankLinesBounds(content)
+	if !ok || restStart == len(content) {
+		return 0, 0, false
+	}
+	return count, l.blankLinesAfter, true
+}
+
+func (l *licenserImpl) normalizeBlankLines(content string) string {
+	if l.blankLinesAfter == 0 {
+		return content
+	}
+	textEnd, count, restStart, ok := l.blankLinesBounds(content)
+	if !ok || restStart == len(content) || count == l.blankLinesAfter {
+		return content
+	}
+	lineEnd := "\n"
+	if usesCRLF(content) {
+		lineEnd = "\r\n"
+	}
+	return content[:textEnd] + strings.Repeat(lineEnd, l.blankLinesAfter) + content[restStart:]
+}
+
+// blankLinesBounds returns the offset of the end of the last non-blank line of the license in the provided content,
+// the number of blank lines that follow it (including the blank lines at the end of the license itself) and the offset
+// of the content after those blank lines. Returns false if the content does not have the license.
+func (l *licenserImpl) blankLinesBounds(content string) (int, int, int, bool) {
+	_, headerEnd, ok := l.headerBounds(content)
+	if !ok {
+		return 0, 0, 0, false
+	}
+	// the license may end with blank lines, which are counted as blank lines after it
+	textEnd := headerEnd
+	count := 0
+	for textEnd > 0 {
+		lineStart := strings.LastIndex(content[:textEnd-1], "\n") + 1
+		if strings.TrimSpace(content[lineStart:textEnd]) != "" {
+			break
+		}
+		textEnd = lineStart
+		count++
+	}
+	restStart := headerEnd
+	for startsWithBlankLine(content[restStart:]) {
+		restStart += strings.Index(content[restStart:], "\n") + 1
+		count++
+	}
+	return textEnd, count, restStart, true
+}
+
+// blankLinesDescription returns a description of the number of blank lines after the license of the provided Licenser
+// in the provided content, such as "3 blank lines, expected 1".
+func blankLinesDescription(licenser Licenser, content string) string {
+	c, ok := licenser.(blankLinesChecker)
+	if !ok {
+		return ""
+	}
+	count, want, ok := c.blankLines(content)
+	if !ok {
+		return ""
+	}
+	unit := "blank lines"
+	if count == 1 {
+		unit = "blank line"
+	}
+	return fmt.Sprintf("%d %s, expected %d", count, unit, want)
+}
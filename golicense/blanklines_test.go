@@ -0,0 +1,122 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlankLinesAfter(t *testing.T) {
+	const header = "// Copyright 2016 Palantir Technologies, Inc."
+
+	for _, tc := range []struct {
+		name            string
+		header          string
+		blankLinesAfter int
+		content         string
+		wantContent     string
+		wantVerify      string
+		wantRemoved     string
+	}{
+		{
+			name:            "header added with one blank line",
+			blankLinesAfter: 1,
+			content:         "package foo\n",
+			wantContent:     header + "\n\npackage foo\n",
+			wantVerify:      "1 file does not have the correct license header:\n\tfoo.go (no license header)\n",
+			wantRemoved:     "package foo\n",
+		},
+		{
+			name:            "header added with two blank lines",
+			blankLinesAfter: 2,
+			content:         "\n\n\n\npackage foo\n",
+			wantContent:     header + "\n\n\npackage foo\n",
+			wantVerify:      "1 file does not have the correct license header:\n\tfoo.go (no license header)\n",
+			wantRemoved:     "\n\n\n\npackage foo\n",
+		},
+		{
+			name:            "configured number of blank lines",
+			blankLinesAfter: 1,
+			content:         header + "\n\npackage foo\n",
+			wantContent:     header + "\n\npackage foo\n",
+			wantRemoved:     "package foo\n",
+		},
+		{
+			name:            "extra blank lines removed",
+			blankLinesAfter: 1,
+			content:         header + "\n\n\n\npackage foo\n",
+			wantContent:     header + "\n\npackage foo\n",
+			wantVerify:      "1 file does not have the configured number of blank lines after the license header:\n\tfoo.go (3 blank lines, expected 1)\n",
+			wantRemoved:     "package foo\n",
+		},
+		{
+			name:            "missing blank line added",
+			blankLinesAfter: 1,
+			content:         header + "\npackage foo\n",
+			wantContent:     header + "\n\npackage foo\n",
+			wantVerify:      "1 file does not have the configured number of blank lines after the license header:\n\tfoo.go (0 blank lines, expected 1)\n",
+			wantRemoved:     "package foo\n",
+		},
+		{
+			name:            "blank line at end of header counted",
+			header:          header + "\n",
+			blankLinesAfter: 2,
+			content:         header + "\n\npackage foo\n",
+			wantContent:     header + "\n\n\npackage foo\n",
+			wantVerify:      "1 file does not have the configured number of blank lines after the license header:\n\tfoo.go (1 blank line, expected 2)\n",
+			wantRemoved:     "package foo\n",
+		},
+		{
+			name:            "CRLF line endings",
+			blankLinesAfter: 1,
+			content:         header + "\r\n\r\n\r\npackage foo\r\n",
+			wantContent:     header + "\r\n\r\npackage foo\r\n",
+			wantVerify:      "1 file does not have the configured number of blank lines after the license header:\n\tfoo.go (2 blank lines, expected 1)\n",
+			wantRemoved:     "package foo\r\n",
+		},
+		{
+			name:            "header without other content",
+			blankLinesAfter: 1,
+			content:         header + "\n",
+			wantContent:     header + "\n",
+			wantRemoved:     "",
+		},
+		{
+			name:        "blank lines not enforced",
+			content:     header + "\n\n\n\npackage foo\n",
+			wantContent: header + "\n\n\n\npackage foo\n",
+			wantRemoved: "\n\npackage foo\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			licenserHeader := tc.header
+			if licenserHeader == "" {
+				licenserHeader = header
+			}
+			projectParam := golicense.ProjectParam{
+				Licenser:        golicense.NewLicenser(licenserHeader),
+				BlankLinesAfter: tc.blankLinesAfter,
+			}
+
+			got, _ := golicense.LicenseContent("foo.go", []byte(tc.content), projectParam)
+			assert.Equal(t, tc.wantContent, string(got))
+
+			buf := &bytes.Buffer{}
+			ok, err := golicense.VerifyFS(fstest.MapFS{"foo.go": &fstest.MapFile{Data: []byte(tc.content)}}, []string{"foo.go"}, projectParam, buf)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantVerify == "", ok)
+			assert.Equal(t, tc.wantVerify, buf.String())
+
+			removed, _ := golicense.UnlicenseContent("foo.go", []byte(tc.content), projectParam)
+			assert.Equal(t, tc.wantRemoved, string(removed))
+		})
+	}
+}
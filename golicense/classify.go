@@ -0,0 +1,360 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/palantir/go-license/golicense/spdx"
+)
+
+// HeaderKind is the kind of header that content starts with relative to the header of a Licenser.
+type HeaderKind int
+
+const (
+	// HeaderKindNone indicates that the content does not start with a header: it does not start with a comment, or
+	// its leading comment is a doc comment of the package clause that has no copyright or known license.
+	HeaderKindNone HeaderKind = iota
+	// HeaderKindCorrect indicates that the content starts with the header of the Licenser.
+	HeaderKindCorrect
+	// HeaderKindOutdatedYear indicates that the content starts with the header of the Licenser with a different
+	// year.
+	HeaderKindOutdatedYear
+	// HeaderKindForeignCopyright indicates that the content starts with a header that has the copyright of a holder
+	// other than the holder of the copyright in the header of the Licenser.
+	HeaderKindForeignCopyright
+	// HeaderKindKnownLicense indicates that the content starts with a header that contains a known license other than
+	// the header of the Licenser.
+	HeaderKindKnownLicense
+	// HeaderKindUnknown indicates that the content starts with a comment that is not recognized as a header.
+	HeaderKindUnknown
+	// HeaderKindAccepted indicates that the content starts with a header that the Licenser accepts in place of its own
+	// header (see NewLicenserWithAccepted). Such content is only classified this way if the Licenser does not match it,
+	// which is the case if ProjectParam.Strict is true.
+	HeaderKindAccepted
+)
+
+func (k HeaderKind) String() string {
+	switch k {
+	case HeaderKindNone:
+		return "none"
+	case HeaderKindCorrect:
+		return "correct"
+	case HeaderKindOutdatedYear:
+		return "outdated year"
+	case HeaderKindForeignCopyright:
+		return "foreign copyright"
+	case HeaderKindKnownLicense:
+		return "known license"
+	case HeaderKindUnknown:
+		return "unknown"
+	case HeaderKindAccepted:
+		return "accepted"
+	default:
+		return "invalid"
+	}
+}
+
+// HeaderClassification describes the header that content starts with.
+type HeaderClassification struct {
+	Kind HeaderKind
+	// License is the identifier of the license that the header contains if it is recognized (an SPDX license
+	// expression or the identifier of a known license). Set for headers of kind HeaderKindForeignCopyright and
+	// HeaderKindKnownLicense.
+	License string
+	// Copyright is the copyright line of the header, if any. Set for headers of kind HeaderKindForeignCopyright and
+	// HeaderKindKnownLicense.
+	Copyright string
+}
+
+// String returns a description of the classification, such as "MIT license header: Copyright 2015 Foo Co.".
+func (c HeaderClassification) String() string {
+	var desc string
+	switch c.Kind {
+	case HeaderKindNone:
+		return "no license header"
+	case HeaderKindCorrect:
+		return "correct license header"
+	case HeaderKindOutdatedYear:
+		return "license header with a different year"
+	case HeaderKindAccepted:
+		return "accepted license header that is not the canonical header"
+	case HeaderKindForeignCopyright:
+		desc = "copyright of another holder"
+		if c.License != "" {
+			desc = fmt.Sprintf("copyright of another holder under %s license", c.License)
+		}
+	case HeaderKindKnownLicense:
+		desc = fmt.Sprintf("%s license header", c.License)
+	default:
+		return "unrecognized header"
+	}
+	if c.Copyright != "" {
+		desc += ": " + c.Copyright
+	}
+	return desc
+}
+
+// ClassifyHeader classifies the header that the provided content starts with relative to the header of the provided
+// Licenser. Content that matches the Licenser is classified as HeaderKindCorrect. Otherwise, the leading comment of the
+// content is compared with the header of the Licenser and with an embedded set of license fingerprints to determine
+// whether the header is the same except for its year, is accepted by the Licenser, has the copyright of another holder
+// or contains a known license.
+func ClassifyHeader(content string, licenser Licenser) HeaderClassification {
+	if licenser.Matches(content) {
+		return HeaderClassification{Kind: HeaderKindCorrect}
+	}
+	if y, ok := licenser.(yearMatcher); ok && y.withAnyYear().Matches(content) {
+		return HeaderClassification{Kind: HeaderKindOutdatedYear}
+	}
+	if a, ok := licenser.(acceptedMatcher); ok && a.matchesAccepted(content) {
+		return HeaderClassification{Kind: HeaderKindAccepted}
+	}
+
+	_, rest := splitProlog(content)
+	leading, ok := leadingComment(rest, classificationStyles(licenser))
+	if !ok {
+		return HeaderClassification{Kind: HeaderKindNone}
+	}
+
+	copyright := copyrightLine(leading.text)
+	license := knownLicense(leading.text)
+	switch {
+	case leading.packageDoc && copyright == "" && license == "":
+		// package documentation rather than a header
+		return HeaderClassification{Kind: HeaderKindNone}
+	case copyright != "" && !sameHolder(copyright, licenser):
+		return HeaderClassification{Kind: HeaderKindForeignCopyright, License: license, Copyright: copyright}
+	case license != "":
+		return HeaderClassification{Kind: HeaderKindKnownLicense, License: license, Copyright: copyright}
+	default:
+		return HeaderClassification{Kind: HeaderKindUnknown}
+	}
+}
+
+// ClassifyContent classifies the header of the provided content relative to the header that applies to it. The path is
+// used only to determine whether the content is processed and which header applies to it (using the same logic as
+// LicenseFiles). Returns false if the content is not processed.
+func ClassifyContent(path string, content []byte, projectParam ProjectParam) (HeaderClassification, bool) {
+	licenser, ok := licenserForFile(path, projectParam)
+	if !ok {
+		return HeaderClassification{}, false
+	}
+	return ClassifyHeader(string(content), licenser), true
+}
+
+// yearMatcher is implemented by Licensers whose header can be matched regardless of the years that it contains.
+type yearMatcher interface {
+	// withAnyYear returns a Licenser for the same header in which every year matches any year.
+	withAnyYear() Licenser
+}
+
+// templater is implemented by Licensers that can provide the template of their header.
+type templater interface {
+	// headerTemplate returns the header as provided to NewLicenser.
+	headerTemplate() string
+}
+
+// classificationStyles returns the comment styles in which the leading comment of content is recognized when it is
+// classified relative to the header of the provided Licenser: the style of the header and its alternate rendering.
+func classificationStyles(licenser Licenser) []CommentStyle {
+	if t, ok := licenser.(templater); ok {
+		for _, style := range []CommentStyle{HashStyle, DashStyle, TemplateStyle} {
+			if _, ok := style.Strip(t.headerTemplate()); ok {
+				return []CommentStyle{style}
+			}
+		}
+	}
+	return []CommentStyle{SlashStyle, BlockStyle}
+}
+
+// comment is the leading comment of content.
+type comment struct {
+	// text of the comment, including its comment markers
+	text string
+	// offset in the content of the end of the line on which the comment ends
+	end int
+	// whether the comment is immediately followed by a package clause, in which case it is the doc comment of the
+	// package
+	packageDoc bool
+}
+
+// leadingComment returns the first comment of the provided content (after any leading blank lines) if it is a comment
+// in one of the provided styles. Consecutive line comments are a single comment. Returns false if the content does not
+// start with a comment.
+func leadingComment(content string, styles []CommentStyle) (comment, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	end := start
+	for _, style := range styles {
+		end = commentEnd(lines, start, style)
+		if end > start {
+			break
+		}
+	}
+	if end == start {
+		return comment{}, false
+	}
+	offset := len(strings.Join(lines[:start], ""))
+	text := strings.Join(lines[start:end], "")
+	return comment{
+		text:       text,
+		end:        offset + len(text),
+		packageDoc: end < len(lines) && strings.HasPrefix(lines[end], "package "),
+	}, true
+}
+
+// commentEnd returns the index of the first line after the comment in the provided style that starts at lines[start].
+// Returns start if lines[start] does not start a comment in the style.
+func commentEnd(lines []string, start int, style CommentStyle) int {
+	if style.LinePrefix != "" {
+		end := start
+		for end < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[end]), style.LinePrefix) {
+			end++
+		}
+		return end
+	}
+	if start >= len(lines) || !strings.HasPrefix(strings.TrimSpace(lines[start]), style.BlockStart) {
+		return start
+	}
+	for end := start; end < len(lines); end++ {
+		line := lines[end]
+		if end == start {
+			line = strings.TrimSpace(line)[len(style.BlockStart):]
+		}
+		if strings.Contains(line, style.BlockEnd) {
+			return end + 1
+		}
+	}
+	return len(lines)
+}
+
+var copyrightRegexp = regexp.MustCompile(`(?im)^[^a-z0-9]*(copyright\b.*?)[ \t*/]*\r?$`)
+
+// copyrightLine returns the first line of the provided comment that starts with "Copyright" without its comment
+// markers. Returns an empty string if there is no such line.
+func copyrightLine(comment string) string {
+	if match := copyrightRegexp.FindStringSubmatch(comment); match != nil {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+var copyrightHolderRegexp = regexp.MustCompile(`(?i)^copyright\s*(?:\(c\)|©)?\s*(?:\d{4}(?:\s*[-,]\s*\d{4})*)?\s*(?:\(c\)|©)?\s*,?\s*(.*)$`)
+
+// entitySuffixes are the words that designate the legal form of a company, which are ignored when comparing holders so
+// that "Foo Technologies" and "Foo Technologies, Inc." are the same holder.
+var entitySuffixes = map[string]struct{}{
+	"inc":  {},
+	"llc":  {},
+	"ltd":  {},
+	"corp": {},
+	"co":   {},
+}
+
+// copyrightHolder returns the normalized holder of the provided copyright line: the text after the years, lowercase,
+// with punctuation, "All rights reserved" and trailing entity suffixes (such as "Inc.") removed.
+func copyrightHolder(copyright string) string {
+	match := copyrightHolderRegexp.FindStringSubmatch(copyright)
+	if match == nil {
+		return ""
+	}
+	holder := strings.ToLower(match[1])
+	if idx := strings.Index(holder, "all rights reserved"); idx >= 0 {
+		holder = holder[:idx]
+	}
+	words := strings.FieldsFunc(holder, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 0x7f)
+	})
+	// a holder that consists only of a suffix (for example, "Co") is kept as is
+	for len(words) > 1 {
+		if _, ok := entitySuffixes[words[len(words)-1]]; !ok {
+			break
+		}
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// sameHolder returns true if the holder of the provided copyright line is the holder of the copyright in the header of
+// the provided Licenser. Returns false if the header of the Licenser cannot be determined or has no copyright.
+func sameHolder(copyright string, licenser Licenser) bool {
+	t, ok := licenser.(templater)
+	if !ok {
+		return false
+	}
+	ours := copyrightLine(strings.ReplaceAll(t.headerTemplate(), "{{YEAR}}", "2000"))
+	if ours == "" {
+		return false
+	}
+	return copyrightHolder(ours) == copyrightHolder(copyright)
+}
+
+//go:embed fingerprints.txt
+var fingerprintsTxt string
+
+type fingerprint struct {
+	license string
+	phrases []string
+}
+
+var fingerprints = func() []fingerprint {
+	var fps []fingerprint
+	for _, line := range strings.Split(fingerprintsTxt, "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		license, phrases, _ := strings.Cut(line, ":")
+		fp := fingerprint{license: strings.TrimSpace(license)}
+		for _, phrase := range strings.Split(phrases, "&&") {
+			fp.phrases = append(fp.phrases, normalizeLicenseText(phrase))
+		}
+		fps = append(fps, fp)
+	}
+	return fps
+}()
+
+// knownLicense returns the license of the provided comment: the expression of its "SPDX-License-Identifier:" line if
+// it has a valid one, or the identifier of the first fingerprint that matches it. Returns an empty string if the
+// license is not recognized.
+func knownLicense(comment string) string {
+	for _, line := range strings.Split(comment, "\n") {
+		if idx := strings.Index(line, spdxTag); idx >= 0 {
+			expr := strings.TrimRight(strings.TrimSpace(line[idx+len(spdxTag):]), "*/ ")
+			if parsed, err := spdx.Parse(expr); err == nil {
+				return parsed.String()
+			}
+		}
+	}
+	text := normalizeLicenseText(comment)
+	for _, fp := range fingerprints {
+		matched := true
+		for _, phrase := range fp.phrases {
+			if !strings.Contains(text, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return fp.license
+		}
+	}
+	return ""
+}
+
+var commentMarkerRegexp = regexp.MustCompile(`(?m)^\s*(//|#|--|/\*+|\*+/|\*|\{\{/\*)|(\*/\s*-?\}\}|\*/)\s*$`)
+
+// normalizeLicenseText returns the provided text in lowercase with comment markers removed and whitespace collapsed
+// to single spaces.
+func normalizeLicenseText(text string) string {
+	text = commentMarkerRegexp.ReplaceAllString(text, " ")
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
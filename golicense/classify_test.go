@@ -0,0 +1,136 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyHeader(t *testing.T) {
+	licenser := golicense.NewLicenser("// Copyright 2016 Palantir Technologies, Inc.\n//\n// License content.\n")
+
+	for _, tc := range []struct {
+		name     string
+		licenser golicense.Licenser
+		content  string
+		want     golicense.HeaderClassification
+		wantDesc string
+	}{
+		{
+			name:     "correct header",
+			content:  "// Copyright 2016 Palantir Technologies, Inc.\n//\n// License content.\n\npackage foo\n",
+			want:     golicense.HeaderClassification{Kind: golicense.HeaderKindCorrect},
+			wantDesc: "correct license header",
+		},
+		{
+			name:     "no comment",
+			content:  "package foo\n",
+			want:     golicense.HeaderClassification{Kind: golicense.HeaderKindNone},
+			wantDesc: "no license header",
+		},
+		{
+			name:     "package documentation only",
+			content:  "// Package foo does things.\npackage foo\n",
+			want:     golicense.HeaderClassification{Kind: golicense.HeaderKindNone},
+			wantDesc: "no license header",
+		},
+		{
+			name:     "different year",
+			content:  "// Copyright 2015 Palantir Technologies, Inc.\n//\n// License content.\n\npackage foo\n",
+			want:     golicense.HeaderClassification{Kind: golicense.HeaderKindOutdatedYear},
+			wantDesc: "license header with a different year",
+		},
+		{
+			name:    "foreign copyright with known license",
+			content: "// Copyright 2009 The Go Authors. All rights reserved.\n// Use of this source code is governed by a BSD-style\n// license that can be found in the LICENSE file.\n\npackage foo\n",
+			want: golicense.HeaderClassification{
+				Kind:      golicense.HeaderKindForeignCopyright,
+				License:   "BSD-3-Clause",
+				Copyright: "Copyright 2009 The Go Authors. All rights reserved.",
+			},
+			wantDesc: "copyright of another holder under BSD-3-Clause license: Copyright 2009 The Go Authors. All rights reserved.",
+		},
+		{
+			name:    "foreign copyright in block comment",
+			content: "/*\n * Copyright (c) 2012 Foo Co.\n */\n\npackage foo\n",
+			want: golicense.HeaderClassification{
+				Kind:      golicense.HeaderKindForeignCopyright,
+				Copyright: "Copyright (c) 2012 Foo Co.",
+			},
+			wantDesc: "copyright of another holder: Copyright (c) 2012 Foo Co.",
+		},
+		{
+			name:    "known license from our holder",
+			content: "// Copyright 2016 Palantir Technologies Inc.\n// Use of this source code is governed by an MIT-style\n// license that can be found in the LICENSE file.\n\npackage foo\n",
+			want: golicense.HeaderClassification{
+				Kind:      golicense.HeaderKindKnownLicense,
+				License:   "MIT",
+				Copyright: "Copyright 2016 Palantir Technologies Inc.",
+			},
+			wantDesc: "MIT license header: Copyright 2016 Palantir Technologies Inc.",
+		},
+		{
+			name:     "known license from our holder with entity suffix",
+			licenser: golicense.NewLicenser("// Copyright 2016 Palantir Technologies\n"),
+			content:  "// Copyright 2016 Palantir Technologies Inc.\n// Use of this source code is governed by an MIT-style\n// license that can be found in the LICENSE file.\n\npackage foo\n",
+			want: golicense.HeaderClassification{
+				Kind:      golicense.HeaderKindKnownLicense,
+				License:   "MIT",
+				Copyright: "Copyright 2016 Palantir Technologies Inc.",
+			},
+			wantDesc: "MIT license header: Copyright 2016 Palantir Technologies Inc.",
+		},
+		{
+			name:    "known license from SPDX line",
+			content: "// SPDX-License-Identifier: mit OR apache-2.0\n\npackage foo\n",
+			want: golicense.HeaderClassification{
+				Kind:    golicense.HeaderKindKnownLicense,
+				License: "MIT OR Apache-2.0",
+			},
+			wantDesc: "MIT OR Apache-2.0 license header",
+		},
+		{
+			name:     "unknown comment",
+			content:  "// Code generated by foo. DO NOT EDIT.\n\npackage foo\n",
+			want:     golicense.HeaderClassification{Kind: golicense.HeaderKindUnknown},
+			wantDesc: "unrecognized header",
+		},
+		{
+			name:     "hash comments",
+			licenser: golicense.NewLicenser("# Copyright 2016 Palantir Technologies, Inc.\n"),
+			content:  "#!/bin/sh\n# Licensed under the Apache License, Version 2.0\n\necho foo\n",
+			want: golicense.HeaderClassification{
+				Kind:    golicense.HeaderKindKnownLicense,
+				License: "Apache-2.0",
+			},
+			wantDesc: "Apache-2.0 license header",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l := tc.licenser
+			if l == nil {
+				l = licenser
+			}
+			got := golicense.ClassifyHeader(tc.content, l)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantDesc, got.String())
+		})
+	}
+}
+
+func TestClassifyContent(t *testing.T) {
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser("// Copyright {{YEAR}} Palantir Technologies, Inc."),
+	}
+	got, ok := golicense.ClassifyContent("foo.go", []byte("// Copyright 2015 Palantir Technologies, Inc.\n\npackage foo\n"), projectParam)
+	assert.True(t, ok)
+	assert.Equal(t, golicense.HeaderKindCorrect, got.Kind)
+
+	_, ok = golicense.ClassifyContent("foo.txt", []byte("foo"), projectParam)
+	assert.False(t, ok)
+}
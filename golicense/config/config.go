@@ -11,6 +11,7 @@ import (
 
 	"github.com/palantir/go-license/golicense"
 	v0 "github.com/palantir/go-license/golicense/config/internal/v0"
+	"github.com/palantir/pkg/matcher"
 	"github.com/pkg/errors"
 )
 
@@ -30,13 +31,60 @@ func (cfg *ProjectConfig) ToParam() (golicense.ProjectParam, error) {
 	if err := validateCustomHeaderParams(customHeaders); err != nil {
 		return golicense.ProjectParam{}, err
 	}
+
+	var fileTypes []golicense.FileTypeParam
+	for _, v := range cfg.FileTypes {
+		v := FileTypeConfig(v)
+		fileTypeVal, err := v.ToParam()
+		if err != nil {
+			return golicense.ProjectParam{}, err
+		}
+		fileTypes = append(fileTypes, fileTypeVal)
+	}
+	if err := validateFileTypeParams(fileTypes); err != nil {
+		return golicense.ProjectParam{}, err
+	}
+	// the built-in file types are always processed (files of those types can be excluded using Exclude) unless a file
+	// type with the same name is configured. They come after the configured file types, which take precedence.
+	for _, builtin := range golicense.BuiltinFileTypes() {
+		if !hasFileType(fileTypes, builtin.Name) {
+			fileTypes = append(fileTypes, builtin)
+		}
+	}
+
+	licenser, err := cfg.headerConfig().licenser()
+	if err != nil {
+		return golicense.ProjectParam{}, err
+	}
+	thirdPartyPolicy, err := golicense.ParseThirdPartyPolicy(cfg.ThirdParty)
+	if err != nil {
+		return golicense.ProjectParam{}, err
+	}
+
 	return golicense.ProjectParam{
-		Licenser:      golicense.NewLicenser(cfg.Header),
-		CustomHeaders: customHeaders,
-		Exclude:       cfg.Exclude.Matcher(),
+		Licenser:         licenser,
+		CustomHeaders:    customHeaders,
+		Exclude:          cfg.Exclude.Matcher(),
+		ThirdPartyPolicy: thirdPartyPolicy,
+		BlankLinesAfter:  cfg.BlankLinesAfter,
+		FileTypes:        fileTypes,
 	}, nil
 }
 
+func (cfg *ProjectConfig) headerConfig() headerConfig {
+	return headerConfig{
+		header:          cfg.Header,
+		headerRegex:     cfg.HeaderRegex,
+		acceptedHeaders: cfg.AcceptedHeaders,
+		headerStyle:     cfg.HeaderStyle,
+		spdx:            cfg.SPDX,
+		notice:          cfg.Notice,
+		copyrightHolder: cfg.CopyrightHolder,
+		copyrightYear:   cfg.CopyrightYear,
+		blankLinesAfter: cfg.BlankLinesAfter,
+	}
+}
+
 func validateCustomHeaderParams(headerParams []golicense.CustomHeaderParam) error {
 	allNames := make(map[string]struct{})
 	collisions := make(map[string]struct{})
@@ -80,6 +128,119 @@ func validateCustomHeaderParams(headerParams []golicense.CustomHeaderParam) erro
 	return nil
 }
 
+// headerConfig is the configuration of a header, which is the same for the default header and the custom headers.
+type headerConfig struct {
+	header          string
+	headerRegex     string
+	acceptedHeaders []string
+	headerStyle     string
+	spdx            string
+	notice          string
+	copyrightHolder string
+	copyrightYear   string
+	blankLinesAfter int
+}
+
+// licenser returns the Licenser for the header. Returns an error if the header is not a valid Go comment once rendered
+// in the header style.
+func (cfg headerConfig) licenser() (golicense.Licenser, error) {
+	text := cfg.header
+	generated := cfg.spdx != "" || cfg.notice != ""
+	switch {
+	case cfg.header != "" && generated || cfg.spdx != "" && cfg.notice != "":
+		return nil, errors.Errorf("only one of header, spdx and notice can be specified")
+	case cfg.spdx != "":
+		var err error
+		if text, err = golicense.SPDXHeader(cfg.spdx, cfg.copyrightHolder); err != nil {
+			return nil, err
+		}
+	case cfg.notice != "":
+		var err error
+		if text, err = golicense.Notice(cfg.notice, cfg.copyrightHolder, ""); err != nil {
+			return nil, err
+		}
+	case cfg.copyrightHolder != "" || cfg.copyrightYear != "":
+		return nil, errors.Errorf("copyright-holder and copyright-year can only be specified with spdx or notice")
+	}
+	if cfg.copyrightYear != "" {
+		text = strings.ReplaceAll(text, "{{YEAR}}", cfg.copyrightYear)
+	}
+
+	header, err := cfg.render(text, generated)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.validateBlankLinesAfter(header); err != nil {
+		return nil, err
+	}
+	var accepted []string
+	for _, acceptedText := range cfg.acceptedHeaders {
+		acceptedHeader, err := cfg.render(acceptedText, false)
+		if err != nil {
+			return nil, err
+		}
+		accepted = append(accepted, acceptedHeader)
+	}
+	if cfg.headerRegex != "" {
+		if header == "" {
+			return nil, errors.Errorf("header-regex can only be specified with header, spdx or notice")
+		}
+		headerRegex := cfg.headerRegex
+		if cfg.headerStyle != "" {
+			// like the header, the regular expression is plain text that is rendered in the header style, which
+			// NewRegexpLicenser does for a regular expression in the "//" style
+			headerRegex = golicense.SlashStyle.Render(headerRegex)
+		}
+		return golicense.NewRegexpLicenser(header, headerRegex, accepted...)
+	}
+	if len(accepted) > 0 {
+		if header == "" {
+			return nil, errors.Errorf("accepted-headers can only be specified with header, spdx or notice")
+		}
+		return golicense.NewLicenserWithAccepted(header, accepted...), nil
+	}
+	return golicense.NewLicenser(header), nil
+}
+
+// validateBlankLinesAfter returns an error if the number of blank lines after the header is invalid for the provided
+// rendered header. The header may end with blank lines, which count as blank lines after it, so it cannot end with more
+// blank lines than the number that is enforced.
+func (cfg headerConfig) validateBlankLinesAfter(header string) error {
+	if cfg.blankLinesAfter < 0 {
+		return errors.Errorf("blank-lines-after must not be negative: %d", cfg.blankLinesAfter)
+	}
+	trimmed := strings.TrimRight(header, " \t\r\n")
+	if trailing := strings.Count(header[len(trimmed):], "\n"); cfg.blankLinesAfter > 0 && trailing > cfg.blankLinesAfter {
+		return errors.Errorf("header ends with %d blank lines, which is more than blank-lines-after (%d)", trailing, cfg.blankLinesAfter)
+	}
+	return nil
+}
+
+// render returns the provided header text rendered in the header style. Returns an error if the rendered header is not
+// a valid Go comment. If generated is true, the text is plain text that is rendered as "//" comments if no header
+// style is specified.
+func (cfg headerConfig) render(text string, generated bool) (string, error) {
+	var header string
+	switch cfg.headerStyle {
+	case "":
+		header = text
+		if generated {
+			// generated headers are plain text, so they are rendered as "//" comments by default
+			header = golicense.SlashStyle.Render(text)
+		}
+	case "line":
+		header = golicense.SlashStyle.Render(text)
+	case "block":
+		header = golicense.BlockStyle.Render(text)
+	default:
+		return "", errors.Errorf(`invalid header style %q: must be "line" or "block"`, cfg.headerStyle)
+	}
+	if err := ValidateHeader(header); err != nil {
+		return "", err
+	}
+	return header, nil
+}
+
 type CustomHeaderConfig v0.CustomHeaderConfig
 
 func ToCustomHeaderConfigs(in []CustomHeaderConfig) []v0.CustomHeaderConfig {
@@ -97,9 +258,90 @@ func (cfg *CustomHeaderConfig) ToParam() (golicense.CustomHeaderParam, error) {
 	if cfg.Name == "" {
 		return golicense.CustomHeaderParam{}, errors.Errorf("custom header name cannot be blank")
 	}
+	licenser, err := cfg.headerConfig().licenser()
+	if err != nil {
+		if headerErr, ok := err.(*HeaderError); ok {
+			headerErr.CustomHeader = cfg.Name
+		}
+		return golicense.CustomHeaderParam{}, errors.Wrapf(err, "invalid configuration for custom header %s", cfg.Name)
+	}
+	thirdPartyPolicy, err := golicense.ParseThirdPartyPolicy(cfg.ThirdParty)
+	if err != nil {
+		return golicense.CustomHeaderParam{}, errors.Wrapf(err, "invalid configuration for custom header %s", cfg.Name)
+	}
 	return golicense.CustomHeaderParam{
+		Name:             cfg.Name,
+		Licenser:         licenser,
+		ThirdPartyPolicy: thirdPartyPolicy,
+		BlankLinesAfter:  cfg.BlankLinesAfter,
+		IncludePaths:     cfg.Paths,
+	}, nil
+}
+
+func (cfg *CustomHeaderConfig) headerConfig() headerConfig {
+	return headerConfig{
+		header:          cfg.Header,
+		headerRegex:     cfg.HeaderRegex,
+		acceptedHeaders: cfg.AcceptedHeaders,
+		headerStyle:     cfg.HeaderStyle,
+		spdx:            cfg.SPDX,
+		notice:          cfg.Notice,
+		copyrightHolder: cfg.CopyrightHolder,
+		copyrightYear:   cfg.CopyrightYear,
+		blankLinesAfter: cfg.BlankLinesAfter,
+	}
+}
+
+type FileTypeConfig v0.FileTypeConfig
+
+func ToFileTypeConfigs(in []FileTypeConfig) []v0.FileTypeConfig {
+	if in == nil {
+		return nil
+	}
+	out := make([]v0.FileTypeConfig, len(in))
+	for i, v := range in {
+		out[i] = v0.FileTypeConfig(v)
+	}
+	return out
+}
+
+func (cfg *FileTypeConfig) ToParam() (golicense.FileTypeParam, error) {
+	if cfg.Name == "" {
+		return golicense.FileTypeParam{}, errors.Errorf("file type name cannot be blank")
+	}
+	if len(cfg.Names) == 0 {
+		return golicense.FileTypeParam{}, errors.Errorf("file type %s must specify at least one name", cfg.Name)
+	}
+	style, err := golicense.ParseCommentStyle(cfg.CommentStyle)
+	if err != nil {
+		return golicense.FileTypeParam{}, errors.Wrapf(err, "invalid configuration for file type %s", cfg.Name)
+	}
+	return golicense.FileTypeParam{
 		Name:         cfg.Name,
-		Licenser:     golicense.NewLicenser(cfg.Header),
-		IncludePaths: cfg.Paths,
+		Matcher:      matcher.Name(cfg.Names...),
+		CommentStyle: style,
 	}, nil
 }
+
+func hasFileType(fileTypeParams []golicense.FileTypeParam, name string) bool {
+	for _, param := range fileTypeParams {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateFileTypeParams(fileTypeParams []golicense.FileTypeParam) error {
+	seen := make(map[string]struct{})
+	for _, param := range fileTypeParams {
+		if param.Name == "go" {
+			return errors.Errorf(`file type name "go" is reserved for "*.go" files`)
+		}
+		if _, ok := seen[param.Name]; ok {
+			return errors.Errorf("file type %s defined multiple times", param.Name)
+		}
+		seen[param.Name] = struct{}{}
+	}
+	return nil
+}
@@ -32,5 +32,5 @@ custom-headers:
 		panic(err)
 	}
 	fmt.Printf("%q", fmt.Sprintf("%+v", cfg))
-	// Output: "{Header:// Copyright 2016 Palantir Technologies, Inc.\n//\n// License content.\n CustomHeaders:[{Name:subproject Header:// Copyright 2016 Palantir Technologies, Inc. All rights reserved.\n// Subproject license.\n Paths:[subprojectDir]}] Exclude:{Names:[] Paths:[]}}"
+	// Output: "{Header:// Copyright 2016 Palantir Technologies, Inc.\n//\n// License content.\n HeaderRegex: AcceptedHeaders:[] HeaderStyle: SPDX: Notice: CopyrightHolder: CopyrightYear: ThirdParty: BlankLinesAfter:0 CustomHeaders:[{Name:subproject Header:// Copyright 2016 Palantir Technologies, Inc. All rights reserved.\n// Subproject license.\n HeaderRegex: AcceptedHeaders:[] HeaderStyle: SPDX: Notice: CopyrightHolder: CopyrightYear: ThirdParty: BlankLinesAfter:0 Paths:[subprojectDir]}] Exclude:{Names:[] Paths:[]} FileTypes:[]}"
 }
@@ -0,0 +1,80 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// HeaderError is the error returned when a header is not a valid Go comment.
+type HeaderError struct {
+	// CustomHeader is the name of the custom header that is invalid. Empty if the default header is invalid.
+	CustomHeader string
+	// Line is the line of the header that is invalid.
+	Line string
+	// Msg describes the problem with the line.
+	Msg string
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("header is not a valid Go comment: %s: %q", e.Msg, e.Line)
+}
+
+// ValidateHeaders returns an error if the default header or any of the custom headers is not a valid Go comment. If a
+// header is not a valid Go comment, the cause of the returned error is a *HeaderError.
+func (cfg *ProjectConfig) ValidateHeaders() error {
+	if _, err := cfg.headerConfig().licenser(); err != nil {
+		return err
+	}
+	for _, v := range cfg.CustomHeaders {
+		v := CustomHeaderConfig(v)
+		if _, err := v.ToParam(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateHeader returns a *HeaderError if the provided header contains anything other than Go comments and
+// whitespace. Adding such a header to a Go file would cause the file to no longer compile.
+func ValidateHeader(header string) error {
+	var headerErr *HeaderError
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(header))
+	var s scanner.Scanner
+	s.Init(file, []byte(header), func(pos token.Position, msg string) {
+		if headerErr == nil {
+			headerErr = newHeaderError(header, pos.Line, msg)
+		}
+	}, scanner.ScanComments)
+	for headerErr == nil {
+		pos, tok, _ := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.COMMENT {
+			headerErr = newHeaderError(header, file.Line(pos), "line is not a comment")
+		}
+	}
+	if headerErr != nil {
+		return headerErr
+	}
+	return nil
+}
+
+func newHeaderError(header string, line int, msg string) *HeaderError {
+	lines := strings.Split(header, "\n")
+	var text string
+	if line > 0 && line <= len(lines) {
+		text = strings.TrimRight(lines[line-1], "\r")
+	}
+	return &HeaderError{
+		Line: text,
+		Msg:  msg,
+	}
+}
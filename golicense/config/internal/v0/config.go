@@ -16,6 +16,56 @@ type ProjectConfig struct {
 	// year will be substituted for it, and when verifying a license, any 4-digit string will be considered a match.
 	Header string `yaml:"header,omitempty"`
 
+	// HeaderRegex is a regular expression that matches the header when verifying or removing it, which can express
+	// variations that {{YEAR}} cannot (for example, an optional "All rights reserved."). Header (or SPDX or Notice) is
+	// still used when the header is added, and must match the regular expression. Each line of the regular expression
+	// must match an entire line of the header, and {{YEAR}} matches any 4-digit string. The regular expression is
+	// specified in the same form as Header: if HeaderStyle is empty, every line must start with "//", and otherwise
+	// it is plain text that is rendered in the style specified by HeaderStyle.
+	HeaderRegex string `yaml:"header-regex,omitempty"`
+
+	// AcceptedHeaders are other headers that are accepted in place of the header, which is useful while a project
+	// transitions from one header to another. Files that start with an accepted header match, but the header is always
+	// added as specified by Header (or SPDX or Notice). Accepted headers are specified in the same form as Header,
+	// including the rendering specified by HeaderStyle.
+	AcceptedHeaders []string `yaml:"accepted-headers,omitempty"`
+
+	// HeaderStyle specifies the comment style of Header. If empty, Header must already be a "//" comment. Otherwise,
+	// Header is plain text that is rendered as a comment in the specified style, which must be "line" ("//"
+	// comments) or "block" (a "/* */" comment).
+	HeaderStyle string `yaml:"header-style,omitempty"`
+
+	// SPDX is an SPDX license expression (for example, "Apache-2.0 OR MIT") that specifies the header instead of
+	// Header. The header is a "SPDX-License-Identifier:" line for the expression, preceded by a copyright line if
+	// CopyrightHolder is specified, rendered in the comment style specified by HeaderStyle. Any header with an
+	// equivalent expression matches.
+	SPDX string `yaml:"spdx,omitempty"`
+
+	// Notice is the identifier of a standard license notice ("Apache-2.0", "BSD-3-Clause", "MIT", "MPL-2.0" or
+	// "proprietary") that specifies the header instead of Header. The notice is rendered for CopyrightHolder, which
+	// must be specified, in the comment style specified by HeaderStyle.
+	Notice string `yaml:"notice,omitempty"`
+
+	// CopyrightHolder is the holder of the copyright of the header specified by SPDX or Notice. If SPDX is specified
+	// and CopyrightHolder is non-empty, the header starts with a "Copyright {{YEAR}} <CopyrightHolder>" line.
+	CopyrightHolder string `yaml:"copyright-holder,omitempty"`
+
+	// CopyrightYear is the year (or range of years) of the copyright of the header specified by SPDX or Notice. If
+	// empty, {{YEAR}} is used.
+	CopyrightYear string `yaml:"copyright-year,omitempty"`
+
+	// ThirdParty specifies how files that start with a third-party header (a leading comment with the copyright of a
+	// holder other than the holder of the copyright in the header) are handled: "add-above" (the default) adds the
+	// header above the third-party header, "skip" leaves the files unmodified, "add-below" adds the header below the
+	// third-party header and "fail" reports the files as third-party code.
+	ThirdParty string `yaml:"third-party,omitempty"`
+
+	// BlankLinesAfter is the number of blank lines between the header and the rest of the content of a file. If it is
+	// positive, adding the header writes exactly this number of blank lines after it, removing the header removes all
+	// of the blank lines after it, and verification reports files that have a different number of blank lines after
+	// the header. If 0 (the default), adding the header ensures that it is followed by at least one blank line.
+	BlankLinesAfter int `yaml:"blank-lines-after,omitempty"`
+
 	// CustomHeaders specifies the custom header parameters. Custom header parameters can be used to specify that
 	// certain directories or files in the project should use a header that is different from "Header".
 	CustomHeaders []CustomHeaderConfig `yaml:"custom-headers,omitempty"`
@@ -23,6 +73,12 @@ type ProjectConfig struct {
 	// Exclude matches the files and directories that should be excluded from consideration for verifying or applying
 	// licenses.
 	Exclude matcher.NamesPathsCfg `yaml:"exclude,omitempty"`
+
+	// FileTypes specifies the types of files other than "*.go" files that should have license headers. The headers
+	// are specified as "//" comments and are rendered in the comment style of each file type. The built-in file types
+	// ("asm", "c", "proto" and "tmpl") are always processed in addition to these unless a file type with the same name
+	// is specified, which replaces the built-in file type. Exclude can be used to skip files of the built-in types.
+	FileTypes []FileTypeConfig `yaml:"file-types,omitempty"`
 }
 
 type CustomHeaderConfig struct {
@@ -34,12 +90,75 @@ type CustomHeaderConfig struct {
 	// year will be substituted for it, and when verifying a license, any 4-digit string will be considered a match.
 	Header string `yaml:"header,omitempty"`
 
+	// HeaderRegex is a regular expression that matches the header when verifying or removing it, which can express
+	// variations that {{YEAR}} cannot (for example, an optional "All rights reserved."). Header (or SPDX or Notice) is
+	// still used when the header is added, and must match the regular expression. Each line of the regular expression
+	// must match an entire line of the header, and {{YEAR}} matches any 4-digit string. The regular expression is
+	// specified in the same form as Header: if HeaderStyle is empty, every line must start with "//", and otherwise
+	// it is plain text that is rendered in the style specified by HeaderStyle.
+	HeaderRegex string `yaml:"header-regex,omitempty"`
+
+	// AcceptedHeaders are other headers that are accepted in place of the header, which is useful while a project
+	// transitions from one header to another. Files that start with an accepted header match, but the header is always
+	// added as specified by Header (or SPDX or Notice). Accepted headers are specified in the same form as Header,
+	// including the rendering specified by HeaderStyle.
+	AcceptedHeaders []string `yaml:"accepted-headers,omitempty"`
+
+	// HeaderStyle specifies the comment style of Header. If empty, Header must already be a "//" comment. Otherwise,
+	// Header is plain text that is rendered as a comment in the specified style, which must be "line" ("//"
+	// comments) or "block" (a "/* */" comment).
+	HeaderStyle string `yaml:"header-style,omitempty"`
+
+	// SPDX is an SPDX license expression (for example, "Apache-2.0 OR MIT") that specifies the header instead of
+	// Header. The header is a "SPDX-License-Identifier:" line for the expression, preceded by a copyright line if
+	// CopyrightHolder is specified, rendered in the comment style specified by HeaderStyle. Any header with an
+	// equivalent expression matches.
+	SPDX string `yaml:"spdx,omitempty"`
+
+	// Notice is the identifier of a standard license notice ("Apache-2.0", "BSD-3-Clause", "MIT", "MPL-2.0" or
+	// "proprietary") that specifies the header instead of Header. The notice is rendered for CopyrightHolder, which
+	// must be specified, in the comment style specified by HeaderStyle.
+	Notice string `yaml:"notice,omitempty"`
+
+	// CopyrightHolder is the holder of the copyright of the header specified by SPDX or Notice. If SPDX is specified
+	// and CopyrightHolder is non-empty, the header starts with a "Copyright {{YEAR}} <CopyrightHolder>" line.
+	CopyrightHolder string `yaml:"copyright-holder,omitempty"`
+
+	// CopyrightYear is the year (or range of years) of the copyright of the header specified by SPDX or Notice. If
+	// empty, {{YEAR}} is used.
+	CopyrightYear string `yaml:"copyright-year,omitempty"`
+
+	// ThirdParty specifies how files that start with a third-party header (a leading comment with the copyright of a
+	// holder other than the holder of the copyright in the header) are handled: "add-above" (the default) adds the
+	// header above the third-party header, "skip" leaves the files unmodified, "add-below" adds the header below the
+	// third-party header and "fail" reports the files as third-party code.
+	ThirdParty string `yaml:"third-party,omitempty"`
+
+	// BlankLinesAfter is the number of blank lines between the header and the rest of the content of a file. If it is
+	// positive, adding the header writes exactly this number of blank lines after it, removing the header removes all
+	// of the blank lines after it, and verification reports files that have a different number of blank lines after
+	// the header. If 0 (the default), adding the header ensures that it is followed by at least one blank line.
+	BlankLinesAfter int `yaml:"blank-lines-after,omitempty"`
+
 	// Paths specifies the paths for which this custom license is applicable. If multiple custom parameters match a
 	// file or directory, the parameter with the longest path match is used. If multiple custom parameters match a
 	// file or directory exactly (match length is equal), it is treated as an error.
 	Paths []string `yaml:"paths,omitempty"`
 }
 
+type FileTypeConfig struct {
+	// Name is the identifier used to identify this file type. Must be unique.
+	Name string `yaml:"name,omitempty"`
+
+	// Names are the regular expressions that match the names of the files of this type (for example, "Dockerfile" or
+	// ".*\.sh"). A file matches if its name or the name of any of its parent directories matches.
+	Names []string `yaml:"names,omitempty"`
+
+	// CommentStyle is the comment style used for the license header of files of this type. Must be one of "//", "#",
+	// "--", "/* */" or "{{/* */}}".
+	CommentStyle string `yaml:"comment-style,omitempty"`
+}
+
 func UpgradeConfig(cfgBytes []byte) ([]byte, error) {
 	var cfg ProjectConfig
 	if err := yaml.UnmarshalStrict(cfgBytes, &cfg); err != nil {
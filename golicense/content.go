@@ -0,0 +1,117 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/pkg/errors"
+)
+
+// Status is the result of processing the content of a single file.
+type Status int
+
+const (
+	// StatusSkipped indicates that the file is not processed because it matches no configured file type ("*.go" files
+	// and the file types of ProjectParam.FileTypes), it is excluded, or no headers are configured. The content is
+	// returned unmodified.
+	StatusSkipped Status = iota
+	// StatusUnchanged indicates that the file is processed, but its content already has the desired license state.
+	StatusUnchanged
+	// StatusModified indicates that the content was modified. When verifying, this indicates that the content does not
+	// have the correct license header.
+	StatusModified
+	// StatusThirdParty indicates that the content is third-party code that is reported by the ThirdPartyFail policy of
+	// the applicable header, so the license cannot be added to it. The content is returned unmodified.
+	StatusThirdParty
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusSkipped:
+		return "skipped"
+	case StatusUnchanged:
+		return "unchanged"
+	case StatusModified:
+		return "modified"
+	case StatusThirdParty:
+		return "third-party"
+	default:
+		return "unknown"
+	}
+}
+
+// LicenseContent returns the provided content with the applicable license header added. The path is used only to
+// determine whether the content is processed and which header applies to it (using the same logic as LicenseFiles):
+// the file system is not accessed. Content that already has the correct license header is returned unmodified, and
+// content that is third-party code that the applicable header reports is returned unmodified with StatusThirdParty.
+func LicenseContent(path string, content []byte, projectParam ProjectParam) ([]byte, Status) {
+	if licenser, ok := licenserForFile(path, projectParam); ok {
+		if _, ok := thirdPartyCode(licenser, string(content)); ok {
+			return content, StatusThirdParty
+		}
+	}
+	return processContent(path, content, projectParam, addLicense)
+}
+
+// UnlicenseContent returns the provided content with the applicable license header removed. The path is used only to
+// determine whether the content is processed and which header applies to it (using the same logic as UnlicenseFiles):
+// the file system is not accessed. Content that does not have the license header is returned unmodified.
+func UnlicenseContent(path string, content []byte, projectParam ProjectParam) ([]byte, Status) {
+	return processContent(path, content, projectParam, removeLicenseFromFile)
+}
+
+// LicenseFS adds the applicable license header to the provided files in the provided file system. The file paths are
+// interpreted as paths in fsys. Because fs.FS is read-only, the file system is not modified: instead, the returned map
+// contains the new content of all of the files that were modified, keyed by path. Returns an error if a file is
+// third-party code that the applicable header reports.
+func LicenseFS(fsys fs.FS, files []string, projectParam ProjectParam) (map[string][]byte, error) {
+	return processFS(fsys, files, projectParam, true, addLicense)
+}
+
+// UnlicenseFS removes the applicable license header from the provided files in the provided file system. The file
+// paths are interpreted as paths in fsys. Because fs.FS is read-only, the file system is not modified: instead, the
+// returned map contains the new content of all of the files that were modified, keyed by path.
+func UnlicenseFS(fsys fs.FS, files []string, projectParam ProjectParam) (map[string][]byte, error) {
+	return processFS(fsys, files, projectParam, false, removeLicenseFromFile)
+}
+
+func processContent(path string, content []byte, projectParam ProjectParam, op func(path string, licenser Licenser, content string) (string, bool)) ([]byte, Status) {
+	licenser, ok := licenserForFile(path, projectParam)
+	if !ok {
+		return content, StatusSkipped
+	}
+	newContent, _ := op(path, licenser, string(content))
+	if newContent == string(content) {
+		return content, StatusUnchanged
+	}
+	return []byte(newContent), StatusModified
+}
+
+func processFS(fsys fs.FS, files []string, projectParam ProjectParam, failThirdParty bool, op func(path string, licenser Licenser, content string) (string, bool)) (map[string][]byte, error) {
+	out := make(map[string][]byte)
+	if _, err := processFiles(context.Background(), files, projectParam, true, func(ctx context.Context, files []string, licenser Licenser, modify bool, observer Observer) ([]string, error) {
+		return visitFS(fsys, files, observer, func(path string, content string) (bool, bool, error) {
+			if copyright, ok := thirdPartyCode(licenser, content); ok && failThirdParty {
+				return false, false, errors.Errorf("%s is third-party code (%s)", path, copyright)
+			}
+			newContent, matched := op(path, licenser, content)
+			changed := newContent != content
+			if changed {
+				out[path] = []byte(newContent)
+			}
+			return matched, changed, nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// removeLicenseFromFile is removeLicense with the signature of addLicense. The path is not used.
+func removeLicenseFromFile(_ string, licenser Licenser, content string) (string, bool) {
+	return removeLicense(licenser, content)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/palantir/pkg/matcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicenseContent(t *testing.T) {
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+		CustomHeaders: []golicense.CustomHeaderParam{
+			{
+				Name:         "Custom Co.",
+				Licenser:     golicense.NewLicenser("// Copyright 2016 Custom Co."),
+				IncludePaths: []string{"bar"},
+			},
+		},
+		Exclude: matcher.Name("excluded.go"),
+	}
+
+	for _, tc := range []struct {
+		name        string
+		path        string
+		content     string
+		remove      bool
+		wantContent string
+		wantStatus  golicense.Status
+	}{
+		{
+			name:    "license added",
+			path:    "foo.go",
+			content: "package foo",
+			wantContent: `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+			wantStatus: golicense.StatusModified,
+		},
+		{
+			name:    "custom license added based on path",
+			path:    "bar/bar.go",
+			content: "package bar",
+			wantContent: `// Copyright 2016 Custom Co.
+
+package bar`,
+			wantStatus: golicense.StatusModified,
+		},
+		{
+			name: "content with license unchanged",
+			path: "foo.go",
+			content: `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+			wantContent: `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+			wantStatus: golicense.StatusUnchanged,
+		},
+		{
+			name: "blank line added after license that is package documentation",
+			path: "foo.go",
+			content: `// Copyright 2016 Palantir Technologies, Inc.
+package foo`,
+			wantContent: `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+			wantStatus: golicense.StatusModified,
+		},
+		{
+			name:        "excluded content skipped",
+			path:        "excluded.go",
+			content:     "package foo",
+			wantContent: "package foo",
+			wantStatus:  golicense.StatusSkipped,
+		},
+		{
+			name:        "non-Go content skipped",
+			path:        "foo.txt",
+			content:     "foo",
+			wantContent: "foo",
+			wantStatus:  golicense.StatusSkipped,
+		},
+		{
+			name:   "license removed",
+			path:   "bar/bar.go",
+			remove: true,
+			content: `// Copyright 2016 Custom Co.
+package bar`,
+			wantContent: "package bar",
+			wantStatus:  golicense.StatusModified,
+		},
+		{
+			name:        "content without license unchanged by remove",
+			path:        "foo.go",
+			remove:      true,
+			content:     "package foo",
+			wantContent: "package foo",
+			wantStatus:  golicense.StatusUnchanged,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []byte
+			var status golicense.Status
+			if tc.remove {
+				got, status = golicense.UnlicenseContent(tc.path, []byte(tc.content), projectParam)
+			} else {
+				got, status = golicense.LicenseContent(tc.path, []byte(tc.content), projectParam)
+			}
+			assert.Equal(t, tc.wantContent, string(got))
+			assert.Equal(t, tc.wantStatus, status)
+		})
+	}
+}
+
+func TestLicenseFS(t *testing.T) {
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+	}
+	fsys := fstest.MapFS{
+		"foo.go": &fstest.MapFile{Data: []byte(`package foo`)},
+		"bar/bar.go": &fstest.MapFile{Data: []byte(`// Copyright 2016 Palantir Technologies, Inc.
+
+package bar`)},
+	}
+	files := []string{"foo.go", "bar/bar.go"}
+
+	got, err := golicense.LicenseFS(fsys, files, projectParam)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"foo.go": []byte(`// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`),
+	}, got)
+
+	got, err = golicense.UnlicenseFS(fsys, files, projectParam)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"bar/bar.go": []byte(`package bar`),
+	}, got)
+}
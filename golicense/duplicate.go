@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"strings"
+)
+
+// duplicateLicense returns the provided content with the license of the provided Licenser removed once and true if the
+// result still matches the license, which is the case if the content starts with the license more than once (for
+// example, because the license was added again after a merge). Blank lines between the repeated licenses are removed
+// along with the first license.
+func duplicateLicense(licenser Licenser, content string) (string, bool) {
+	removed := licenser.Remove(content)
+	if removed == content {
+		return "", false
+	}
+	prolog, rest := splitProlog(removed)
+	for startsWithBlankLine(rest) {
+		rest = trimBlankLine(rest)
+	}
+	removed = prolog + rest
+	return removed, licenser.Matches(removed)
+}
+
+// collapseDuplicateLicense returns the provided content with its repeated licenses collapsed into a single license. The
+// first of the repeated licenses is kept if the Licenser can locate its license in content (so that the canonical
+// rendering is kept if it comes first), and the last one is kept otherwise. The kept license is separated from the rest
+// of the content by a blank line in the same manner as by Add. Content that does not start with the license more than
+// once is returned unmodified.
+func collapseDuplicateLicense(licenser Licenser, content string) string {
+	collapsed := content
+	for {
+		removed, ok := duplicateLicense(licenser, collapsed)
+		if !ok {
+			break
+		}
+		collapsed = removed
+	}
+	if collapsed == content {
+		return content
+	}
+	if locator, ok := licenser.(headerLocator); ok {
+		_, firstEnd, firstOK := locator.headerBounds(content)
+		_, lastEnd, lastOK := locator.headerBounds(collapsed)
+		if firstOK && lastOK {
+			kept, rest := content[:firstEnd], collapsed[lastEnd:]
+			// the first license may have been followed directly by the license that was removed, so ensure that it is
+			// still separated from the rest of the content
+			if lines := strings.Split(kept, "\n"); rest != "" && !startsWithBlankLine(rest) && strings.TrimSpace(lines[len(lines)-2]) != "" {
+				lineEnd := "\n"
+				if usesCRLF(content) {
+					lineEnd = "\r\n"
+				}
+				kept += lineEnd
+			}
+			return kept + rest
+		}
+	}
+	return collapsed
+}
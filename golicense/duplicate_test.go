@@ -0,0 +1,94 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicateLicense(t *testing.T) {
+	cType, ok := golicense.BuiltinFileType("c")
+	require.True(t, ok)
+	projectParam := golicense.ProjectParam{
+		Licenser:  golicense.NewLicenser("// Copyright {{YEAR}} Palantir Technologies, Inc."),
+		FileTypes: []golicense.FileTypeParam{cType},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		path        string
+		content     string
+		wantContent string
+	}{
+		{
+			name:        "single header",
+			content:     "// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n",
+			wantContent: "// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n",
+		},
+		{
+			name:        "repeated header",
+			content:     "// Copyright 2017 Palantir Technologies, Inc.\n\n// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n",
+			wantContent: "// Copyright 2017 Palantir Technologies, Inc.\n\npackage foo\n",
+		},
+		{
+			name:        "header repeated three times without blank lines",
+			content:     "// Copyright 2018 Palantir Technologies, Inc.\n// Copyright 2017 Palantir Technologies, Inc.\n// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n",
+			wantContent: "// Copyright 2018 Palantir Technologies, Inc.\n\npackage foo\n",
+		},
+		{
+			name:        "repeated header separated by multiple blank lines",
+			content:     "// Copyright 2016 Palantir Technologies, Inc.\n\n\n\n// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n",
+			wantContent: "// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n",
+		},
+		{
+			name:        "header repeated in other comment style",
+			content:     "#!/usr/bin/env foo\n// Copyright 2016 Palantir Technologies, Inc.\n\n/*\nCopyright 2016 Palantir Technologies, Inc.\n*/\n\npackage foo\n",
+			wantContent: "#!/usr/bin/env foo\n// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n",
+		},
+		{
+			name:        "header repeated in other comment style without blank lines",
+			path:        "foo.c",
+			content:     "// Copyright 2016 Palantir Technologies, Inc.\n/*\nCopyright 2016 Palantir Technologies, Inc.\n*/\nint foo;\n",
+			wantContent: "// Copyright 2016 Palantir Technologies, Inc.\n\nint foo;\n",
+		},
+		{
+			name:        "header repeated without blank lines with CRLF line endings",
+			path:        "foo.c",
+			content:     "// Copyright 2017 Palantir Technologies, Inc.\r\n// Copyright 2016 Palantir Technologies, Inc.\r\nint foo;\r\n",
+			wantContent: "// Copyright 2017 Palantir Technologies, Inc.\r\n\r\nint foo;\r\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := tc.path
+			if path == "" {
+				path = "foo.go"
+			}
+			got, _ := golicense.LicenseContent(path, []byte(tc.content), projectParam)
+			assert.Equal(t, tc.wantContent, string(got))
+
+			buf := &bytes.Buffer{}
+			ok, err := golicense.VerifyFS(fstest.MapFS{path: &fstest.MapFile{Data: []byte(tc.content)}}, []string{path}, projectParam, buf)
+			require.NoError(t, err)
+			if tc.content == tc.wantContent {
+				assert.True(t, ok)
+				assert.Empty(t, buf.String())
+				return
+			}
+			assert.False(t, ok)
+			assert.Equal(t, "1 file has a duplicated license header:\n\t"+path+"\n", buf.String())
+
+			buf.Reset()
+			err = golicense.RunLicenseStdin(path, projectParam, true, false, bytes.NewReader([]byte(tc.content)), buf)
+			assert.Error(t, err)
+			assert.Equal(t, "1 file has a duplicated license header:\n\t"+path+"\n", buf.String())
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package gitrev provides read-only access to the files in the tree of a git revision of a local repository. It is
+// used to verify license headers at an arbitrary revision without checking it out.
+package gitrev
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FS is an fs.FS that provides the content of the files in the tree of a git revision. All paths are relative to the
+// directory that was used to create the FS, which must be inside the git repository. The content of files is read
+// using a single "git cat-file --batch" process that is started by the first read and that runs until Close is called.
+type FS struct {
+	dir      string
+	rev      string
+	tree     string
+	prefix   string
+	topLevel string
+
+	mu    sync.Mutex
+	batch *catFileBatch
+}
+
+// New returns an FS for the tree of the provided revision. The revision may be any value that "git rev-parse"
+// resolves to a tree-ish (a commit hash, tag, branch name, etc.). The provided directory must be inside a git
+// repository and the paths of the returned FS are relative to it.
+func New(dir, rev string) (*FS, error) {
+	tree, err := runGit(dir, "rev-parse", "--verify", "--quiet", rev+"^{tree}")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve revision %s", rev)
+	}
+	prefix, err := runGit(dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine location of %s in git repository", dir)
+	}
+	topLevel, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine root of git repository of %s", dir)
+	}
+	return &FS{
+		dir:      dir,
+		rev:      rev,
+		tree:     strings.TrimSpace(string(tree)),
+		prefix:   strings.TrimSpace(string(prefix)),
+		topLevel: strings.TrimSpace(string(topLevel)),
+	}, nil
+}
+
+// TopLevel returns the absolute path of the root directory of the git repository.
+func (f *FS) TopLevel() string {
+	return f.topLevel
+}
+
+// Files returns the paths of all of the regular files in the tree of the revision that are in the directory of the
+// FS (or one of its subdirectories). Symbolic links and submodules are not included. The returned paths are sorted.
+func (f *FS) Files() ([]string, error) {
+	args := []string{"ls-tree", "-r", "-z", "--full-tree", f.tree}
+	if f.prefix != "" {
+		args = append(args, "--", f.prefix)
+	}
+	out, err := runGit(f.dir, args...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list files in revision %s", f.rev)
+	}
+
+	var files []string
+	for _, entry := range strings.Split(string(out), "\x00") {
+		if entry == "" {
+			continue
+		}
+		// each entry has the form "<mode> SP <type> SP <object> TAB <file>"
+		meta, name, ok := strings.Cut(entry, "\t")
+		if !ok {
+			return nil, errors.Errorf("unexpected ls-tree output: %q", entry)
+		}
+		if fields := strings.Fields(meta); len(fields) != 3 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		files = append(files, strings.TrimPrefix(name, f.prefix))
+	}
+	return files, nil
+}
+
+// Open opens the named file. Only regular files can be opened: opening a directory returns an error.
+func (f *FS) Open(name string) (fs.File, error) {
+	content, err := f.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &file{
+		Reader: bytes.NewReader(content),
+		info: fileInfo{
+			name: path.Base(name),
+			size: int64(len(content)),
+		},
+	}, nil
+}
+
+// ReadFile returns the content of the named file in the tree of the revision. If the file does not exist in the
+// revision, the returned error wraps fs.ErrNotExist.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) || strings.ContainsAny(name, "\r\n") {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return f.readBlob(name, f.prefix+name)
+}
+
+// ReadRepoFile returns the content of the named file in the tree of the revision, where the name is relative to the
+// root of the git repository rather than to the directory of the FS. If the file does not exist in the revision, the
+// returned error wraps fs.ErrNotExist.
+func (f *FS) ReadRepoFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) || strings.ContainsAny(name, "\r\n") {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	return f.readBlob(name, name)
+}
+
+// readBlob returns the content of the file at the provided path relative to the root of the tree of the revision. The
+// provided name is used in errors.
+func (f *FS) readBlob(name, treePath string) ([]byte, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.batch == nil {
+		batch, err := startCatFileBatch(f.dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s in revision %s", name, f.rev)
+		}
+		f.batch = batch
+	}
+	objectType, content, err := f.batch.read(f.tree + ":" + treePath)
+	if err != nil {
+		// the output of the process can no longer be interpreted, so the next read starts a new one
+		closeErr := f.batch.close()
+		f.batch = nil
+		if closeErr != nil {
+			return nil, errors.Wrapf(err, "failed to read %s in revision %s (%v)", name, f.rev, closeErr)
+		}
+		return nil, errors.Wrapf(err, "failed to read %s in revision %s", name, f.rev)
+	}
+	switch objectType {
+	case "missing":
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case "blob":
+		return content, nil
+	default:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.Errorf("%s is a %s, not a file", name, objectType)}
+	}
+}
+
+// Close stops the "git cat-file --batch" process that reads the content of files, if it was started. Files can still
+// be read after Close is called, in which case a new process is started.
+func (f *FS) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.batch == nil {
+		return nil
+	}
+	err := f.batch.close()
+	f.batch = nil
+	return err
+}
+
+// catFileBatch is a running "git cat-file --batch" process. Batch mode reports missing objects in its output rather
+// than failing, so a single process can read any number of objects.
+type catFileBatch struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr bytes.Buffer
+}
+
+func startCatFileBatch(dir string) (*catFileBatch, error) {
+	b := &catFileBatch{
+		cmd: exec.Command("git", "cat-file", "--batch"),
+	}
+	b.cmd.Dir = dir
+	b.cmd.Stderr = &b.stderr
+	stdin, err := b.cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to start git cat-file --batch")
+	}
+	stdout, err := b.cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to start git cat-file --batch")
+	}
+	if err := b.cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "failed to start git cat-file --batch")
+	}
+	b.stdin = stdin
+	b.stdout = bufio.NewReader(stdout)
+	return b, nil
+}
+
+// read returns the type and the content of the provided object. The type is "missing" if the object does not exist.
+func (b *catFileBatch) read(object string) (string, []byte, error) {
+	if _, err := io.WriteString(b.stdin, object+"\n"); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to write to git cat-file --batch")
+	}
+	header, err := b.stdout.ReadString('\n')
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to read output of git cat-file --batch")
+	}
+	// the header is "<object> missing" or "<object> SP <type> SP <size>"
+	fields := strings.Fields(header)
+	if len(fields) == 2 && fields[1] == "missing" {
+		return "missing", nil, nil
+	}
+	if len(fields) != 3 {
+		return "", nil, errors.Errorf("unexpected cat-file output: %q", header)
+	}
+	size, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "unexpected cat-file output: %q", header)
+	}
+	// the content is followed by a newline
+	content := make([]byte, size+1)
+	if _, err := io.ReadFull(b.stdout, content); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to read output of git cat-file --batch")
+	}
+	return fields[1], content[:size], nil
+}
+
+// close closes the input of the process, which causes it to exit, and waits for it to exit.
+func (b *catFileBatch) close() error {
+	_ = b.stdin.Close()
+	if err := b.cmd.Wait(); err != nil {
+		return errors.Wrapf(err, "git cat-file --batch failed: %s", strings.TrimSpace(b.stderr.String()))
+	}
+	return nil
+}
+
+func runGit(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+type file struct {
+	*bytes.Reader
+	info fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *file) Close() error {
+	return nil
+}
+
+type fileInfo struct {
+	name string
+	size int64
+}
+
+func (fi fileInfo) Name() string       { return fi.name }
+func (fi fileInfo) Size() int64        { return fi.size }
+func (fi fileInfo) Mode() fs.FileMode  { return 0444 }
+func (fi fileInfo) ModTime() time.Time { return time.Time{} }
+func (fi fileInfo) IsDir() bool        { return false }
+func (fi fileInfo) Sys() interface{}   { return nil }
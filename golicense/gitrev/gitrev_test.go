@@ -0,0 +1,99 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package gitrev_test
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/go-license/golicense/gitrev"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFS(t *testing.T) {
+	tmpDir := t.TempDir()
+	runGit(t, tmpDir, "init")
+	writeFile(t, filepath.Join(tmpDir, "foo.go"), "package foo")
+	writeFile(t, filepath.Join(tmpDir, "bar", "bar.go"), "package bar")
+	runGit(t, tmpDir, "add", ".")
+	runGit(t, tmpDir, "commit", "-m", "first")
+	runGit(t, tmpDir, "tag", "v1")
+
+	// modify working tree and commit again: FS should still provide the content of the tagged revision
+	writeFile(t, filepath.Join(tmpDir, "foo.go"), "// changed\npackage foo")
+	writeFile(t, filepath.Join(tmpDir, "bar", "baz.go"), "package bar")
+	runGit(t, tmpDir, "add", ".")
+	runGit(t, tmpDir, "commit", "-m", "second")
+
+	fsys, err := gitrev.New(tmpDir, "v1")
+	require.NoError(t, err)
+
+	files, err := fsys.Files()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar/bar.go", "foo.go"}, files)
+
+	content, err := fs.ReadFile(fsys, "foo.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package foo", string(content))
+
+	_, err = fs.ReadFile(fsys, "bar/baz.go")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	// files are read using a single process, which continues to be used after a missing file and after a directory
+	_, err = fs.ReadFile(fsys, "bar")
+	assert.EqualError(t, err, "open bar: bar is a tree, not a file")
+	content, err = fs.ReadFile(fsys, "bar/bar.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package bar", string(content))
+
+	// files can still be read after the process is stopped
+	require.NoError(t, fsys.Close())
+	content, err = fs.ReadFile(fsys, "foo.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package foo", string(content))
+	require.NoError(t, fsys.Close())
+
+	// paths are relative to the directory used to create the FS
+	subFS, err := gitrev.New(filepath.Join(tmpDir, "bar"), "v1")
+	require.NoError(t, err)
+	files, err = subFS.Files()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar.go"}, files)
+	content, err = fs.ReadFile(subFS, "bar.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package bar", string(content))
+
+	// files outside of the directory can be read using paths relative to the root of the repository
+	content, err = subFS.ReadRepoFile("foo.go")
+	require.NoError(t, err)
+	assert.Equal(t, "package foo", string(content))
+	_, err = subFS.ReadRepoFile("bar/baz.go")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	topLevel, err := filepath.EvalSymlinks(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, topLevel, subFS.TopLevel())
+	require.NoError(t, subFS.Close())
+
+	_, err = gitrev.New(tmpDir, "no-such-revision")
+	assert.Error(t, err)
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v failed: %s", args, string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	require.NoError(t, err)
+	err = os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err)
+}
@@ -5,8 +5,10 @@
 package golicense
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"regexp"
 	"sort"
@@ -14,17 +16,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/palantir/go-license/golicense/spdx"
 	"github.com/palantir/pkg/matcher"
 	"github.com/pkg/errors"
 )
 
 type Licenser interface {
-	// Add adds the license to the provided content.
+	// Add adds the license to the provided content. If the content begins with a prolog that must remain at the start
+	// of the file (such as a byte order mark or a "#!" interpreter directive), the license is added after it.
 	Add(content string) string
 	// Remove removes the license to the provided content.
 	Remove(content string) string
-	// Matches returns true if the provided content starts with the license in this Licenser. This is not necessarily
-	// a literal prefix match of LicenseHeader (because any year may match).
+	// Matches returns true if the provided content starts with the license in this Licenser (after any prolog). This is
+	// not necessarily a literal prefix match of LicenseHeader (because any year may match).
 	Matches(content string) bool
 	// Empty returns true if no license header exists.
 	Empty() bool
@@ -49,118 +53,477 @@ func RunLicense(files []string, projectParam ProjectParam, verify, remove bool,
 	}
 }
 
+// RunLicenseStdin runs the license operation on the content read from stdin as if it were the content of the file at
+// the provided path. The path is used only to determine whether the file is excluded and which header applies to it:
+// the file system is not read or modified. When adding or removing a license, the resulting content is written to
+// stdout (content for files that are not processed is written unmodified); adding a license to third-party code that
+// the applicable header reports fails with an error, and nothing is written. When verifying, nothing is written if the
+// content has the correct license header; otherwise, the problem is written in the same manner as by VerifyFiles and
+// an empty error is returned.
+func RunLicenseStdin(path string, projectParam ProjectParam, verify, remove bool, stdin io.Reader, stdout io.Writer) error {
+	bytes, err := io.ReadAll(stdin)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read stdin")
+	}
+	switch {
+	case verify:
+		if licenser, ok := licenserForFile(path, projectParam); ok {
+			result := make(verifyResult)
+			if _, failed := result.verify(path, string(bytes), licenser); failed {
+				result.print(stdout)
+				return fmt.Errorf("")
+			}
+		}
+		return nil
+	case remove:
+		bytes, _ = UnlicenseContent(path, bytes, projectParam)
+	default:
+		if licenser, ok := licenserForFile(path, projectParam); ok {
+			if copyright, ok := thirdPartyCode(licenser, string(bytes)); ok {
+				return errors.Errorf("%s is third-party code (%s)", path, copyright)
+			}
+		}
+		bytes, _ = LicenseContent(path, bytes, projectParam)
+	}
+	if _, err := stdout.Write(bytes); err != nil {
+		return errors.Wrapf(err, "failed to write output")
+	}
+	return nil
+}
+
+// licenserForFile returns the Licenser that applies to the provided file. Returns false if the file should not be
+// processed.
+func licenserForFile(file string, projectParam ProjectParam) (Licenser, bool) {
+	if projectParam.Licenser.Empty() && len(projectParam.CustomHeaders) == 0 {
+		return nil, false
+	}
+	if !isIncluded(file, projectParam) {
+		return nil, false
+	}
+	fileType, _ := fileTypeForFile(file, projectParam)
+	if customHeader, ok := customHeaderForFile(file, projectParam.CustomHeaders); ok {
+		return licenserForFileType(customHeader.configuredLicenser(), fileType, projectParam), true
+	}
+	return licenserForFileType(projectParam.configuredLicenser(), fileType, projectParam), true
+}
+
+// configuredLicenser returns the default Licenser with the third-party and blank line policies of projectParam applied.
+func (projectParam ProjectParam) configuredLicenser() Licenser {
+	return licenserForBlankLines(licenserForThirdPartyPolicy(projectParam.Licenser, projectParam.ThirdPartyPolicy), projectParam.BlankLinesAfter)
+}
+
+// configuredLicenser returns the Licenser of the custom header with its third-party and blank line policies applied.
+func (customHeader CustomHeaderParam) configuredLicenser() Licenser {
+	return licenserForBlankLines(licenserForThirdPartyPolicy(customHeader.Licenser, customHeader.ThirdPartyPolicy), customHeader.BlankLinesAfter)
+}
+
 type licenserImpl struct {
+	// the license as provided to NewLicenser, which may contain "{{YEAR}}"
+	template string
 	// literal license to add for new files
 	newLicenseHeader string
-	// regular expression that matches the license (if nil, the literal content of newLicenseHeader is used)
+	// regular expression that matches the license, tolerating CRLF line endings and trailing whitespace. The years
+	// that match "{{YEAR}}" are captured by groups named "year".
 	matchRegexp *regexp.Regexp
+	// other renderings of the same license that are also matched and removed (for example, the "/* */" rendering of a
+	// "//" license). The license is always added using newLicenseHeader.
+	alternates []*licenserImpl
+	// if true, alternate renderings are not matched, and Add replaces an alternate rendering with newLicenseHeader
+	convert bool
+	// other headers that are accepted in place of the license (for example, the header that a project is transitioning
+	// from). They are matched and removed, but the license is always added using newLicenseHeader.
+	accepted []*licenserImpl
+	// if true, accepted headers are not matched, and Add replaces an accepted header with newLicenseHeader
+	strict bool
+	// policy for content that starts with a third-party header
+	thirdParty ThirdPartyPolicy
+	// number of blank lines between the license and the rest of the content that Add and Remove enforce. If 0, Add
+	// ensures that there is at least one blank line and Remove removes the blank line that Add inserts.
+	blankLinesAfter int
+	// regular expression provided to NewRegexpLicenser, in the "//" style, from which matchRegexp was compiled once
+	// rendered in the style of template, or empty if matchRegexp was derived from template
+	headerRegexp string
+	// SPDX license expressions of the "SPDX-License-Identifier:" lines of the license in order. matchRegexp captures
+	// the expression of each such line in a group named "spdx", which matches if it is equivalent to the corresponding expression.
+	spdxExprs []*spdx.Expression
 }
 
 func (l *licenserImpl) Add(content string) string {
-	return l.newLicenseHeader + "\n" + content
+	prolog, content := splitProlog(content)
+	if l.thirdParty != ThirdPartyAddAbove {
+		if end, _, ok := l.thirdPartyHeader(content); ok {
+			if l.thirdParty != ThirdPartyAddBelow {
+				return prolog + content
+			}
+			// keep the third-party header at the start of the file, separated from the license by a blank line
+			prolog, content = prolog+content[:end], content[end:]
+			lineEnd := "\n"
+			if usesCRLF(prolog) {
+				lineEnd = "\r\n"
+			}
+			if !strings.HasSuffix(prolog, "\n") {
+				prolog += lineEnd
+			}
+			if lines := strings.Split(prolog, "\n"); strings.TrimSpace(lines[len(lines)-2]) != "" {
+				prolog += lineEnd
+			}
+		}
+	}
+	if l.convert {
+		if end, ok := l.alternateEnd(content); ok {
+			content = content[end:]
+		}
+	}
+	if l.strict {
+		if end, ok := l.acceptedEnd(content, true); ok {
+			content = content[end:]
+		}
+	}
+	header := strings.ReplaceAll(l.newLicenseHeader, "\r\n", "\n") + "\n"
+	switch {
+	case l.blankLinesAfter > 0 && content != "":
+		for startsWithBlankLine(content) {
+			content = trimBlankLine(content)
+		}
+		header = strings.TrimRight(header, "\n") + "\n" + strings.Repeat("\n", l.blankLinesAfter)
+	case !strings.HasSuffix(header, "\n\n") && content != "" && !startsWithBlankLine(content):
+		// ensure that the license is separated from the content by a blank line so that it is not treated as the doc
+		// comment of the package clause (or merged into the comment that follows it)
+		header += "\n"
+	}
+	if usesCRLF(prolog + content) {
+		header = strings.ReplaceAll(header, "\n", "\r\n")
+	}
+	return prolog + header + content
 }
 
 func (l *licenserImpl) Remove(content string) string {
-	if l.matchRegexp == nil {
-		return strings.TrimPrefix(content, l.newLicenseHeader+"\n")
+	prolog, rest := splitProlog(content)
+	start := l.licenseStart(rest)
+	prolog, rest = prolog+rest[:start], rest[start:]
+	end, ok := l.headerEnd(rest)
+	if !ok {
+		end, ok = l.alternateEnd(rest)
+	}
+	if !ok {
+		if end, ok = l.acceptedEnd(rest, true); !ok {
+			return content
+		}
 	}
-	matchLoc := l.matchRegexp.FindStringIndex(content)
-	return content[matchLoc[1]:]
+	rest = rest[end:]
+	switch {
+	case l.blankLinesAfter > 0:
+		for startsWithBlankLine(rest) {
+			rest = trimBlankLine(rest)
+		}
+	case !strings.HasSuffix(l.template, "\n"):
+		// remove the blank line that Add inserts after licenses that do not end with a blank line
+		rest = trimBlankLine(rest)
+	}
+	return prolog + rest
 }
 
 func (l *licenserImpl) Matches(content string) bool {
-	if l.matchRegexp == nil {
-		return strings.HasPrefix(content, l.newLicenseHeader+"\n")
+	_, content = splitProlog(content)
+	switch l.thirdParty {
+	case ThirdPartySkip, ThirdPartyFail:
+		if _, _, ok := l.thirdPartyHeader(content); ok {
+			// skipped content matches, and third-party code is never considered to have the license
+			return l.thirdParty == ThirdPartySkip
+		}
+	case ThirdPartyAddBelow:
+		content = content[l.licenseStart(content):]
+	}
+	if _, ok := l.headerEnd(content); ok {
+		return true
+	}
+	if _, ok := l.alternateEnd(content); ok && !l.convert {
+		return true
 	}
-	matchLoc := l.matchRegexp.FindStringIndex(content)
-	return len(matchLoc) > 0 && matchLoc[0] == 0
+	if l.strict {
+		return false
+	}
+	_, ok := l.acceptedEnd(content, !l.convert)
+	return ok
 }
 
 func (l *licenserImpl) Empty() bool {
-	return l.newLicenseHeader == "" && l.matchRegexp == nil
+	return l.template == ""
 }
 
-func NewLicenser(license string) Licenser {
-	// if special "{{YEAR}}" replacement string is not present, use literal only
-	if !strings.Contains(license, "{{YEAR}}") {
-		return &licenserImpl{
-			newLicenseHeader: license,
+// headerEnd returns the index of the end of the license (including its final newline) if the provided content starts
+// with it. Alternate renderings are not considered.
+func (l *licenserImpl) headerEnd(content string) (int, bool) {
+	matchLoc, ok := l.match(content)
+	if !ok {
+		return 0, false
+	}
+	return matchLoc[1], true
+}
+
+// match returns the submatch indices of matchRegexp in the provided content if the content starts with the license.
+// Alternate renderings are not considered.
+func (l *licenserImpl) match(content string) ([]int, bool) {
+	matchLoc := l.matchRegexp.FindStringSubmatchIndex(content)
+	if len(matchLoc) == 0 || matchLoc[0] != 0 || !spdxExpressionsMatch(content, l.matchRegexp, matchLoc, l.spdxExprs) {
+		return nil, false
+	}
+	return matchLoc, true
+}
+
+// alternateEnd is like headerEnd, but for the alternate renderings of the license.
+func (l *licenserImpl) alternateEnd(content string) (int, bool) {
+	for _, alternate := range l.alternates {
+		if end, ok := alternate.headerEnd(content); ok {
+			return end, true
 		}
 	}
+	return 0, false
+}
+
+func (l *licenserImpl) headerBounds(content string) (int, int, bool) {
+	prolog, rest := splitProlog(content)
+	start := l.licenseStart(rest)
+	prolog, rest = prolog+rest[:start], rest[start:]
+	end, ok := l.headerEnd(rest)
+	if !ok && !l.convert {
+		end, ok = l.alternateEnd(rest)
+	}
+	if !ok && !l.strict {
+		end, ok = l.acceptedEnd(rest, !l.convert)
+	}
+	if !ok {
+		return 0, 0, false
+	}
+	return len(prolog), len(prolog) + end, true
+}
 
-	// create a regexp that matches the provided literal header and `\d\d\d\d` for `{{YEAR}}` with a final newline
-	parts := strings.Split(license, "{{YEAR}}")
-	for i, part := range parts {
-		parts[i] = regexp.QuoteMeta(part)
+// licenseStart returns the offset in the provided content (without its prolog) at which the license starts (or is
+// added). This is the end of the third-party header that the content starts with if the third-party policy is
+// ThirdPartyAddBelow, and 0 otherwise.
+func (l *licenserImpl) licenseStart(content string) int {
+	if l.thirdParty != ThirdPartyAddBelow {
+		return 0
 	}
+	end, _, _ := l.thirdPartyHeader(content)
+	return end
+}
+
+// startsWithBlankLine returns true if the first line of the provided content is empty or consists only of whitespace.
+func startsWithBlankLine(content string) bool {
+	idx := strings.Index(content, "\n")
+	return idx >= 0 && strings.TrimSpace(content[:idx]) == ""
+}
+
+// trimBlankLine returns the provided content with its first line removed if it is blank.
+func trimBlankLine(content string) string {
+	if !startsWithBlankLine(content) {
+		return content
+	}
+	return content[strings.Index(content, "\n")+1:]
+}
+
+// usesCRLF returns true if the first line ending of the provided content is CRLF.
+func usesCRLF(content string) bool {
+	idx := strings.Index(content, "\n")
+	return idx > 0 && content[idx-1] == '\r'
+}
+
+// NewLicenser returns a Licenser for the provided license header. Any occurrences of the string "{{YEAR}}" in the
+// header are replaced by the current year when the license is added and match any 4-digit number when the license is
+// matched or removed. Matching tolerates CRLF line endings and trailing whitespace, and the license is added using the
+// line endings of the content to which it is added. If the header is a "//" comment, the same text rendered as a
+// "/* */" comment is also matched and removed (and vice versa), but the license is always added as provided.
+func NewLicenser(license string) Licenser {
+	l := newLicenserImpl(license)
+	if text, ok := SlashStyle.Strip(license); ok {
+		l.alternates = append(l.alternates, newLicenserImpl(BlockStyle.Render(text)))
+	} else if text, ok := BlockStyle.Strip(license); ok {
+		l.alternates = append(l.alternates, newLicenserImpl(SlashStyle.Render(text)))
+	}
+	return l
+}
+
+func newLicenserImpl(license string) *licenserImpl {
+	// create a regexp that matches the provided literal header and `\d\d\d\d` for `{{YEAR}}` with a final newline.
+	// Line endings may be CRLF and may be preceded by trailing whitespace. The years are captured so that the year of
+	// a matched license can be determined, and the expressions of SPDX lines are captured so that equivalent
+	// expressions can be matched.
+	var spdxExprs []*spdx.Expression
+	lines := strings.Split(license, "\n")
+	for i, line := range lines {
+		if pattern, expr, ok := spdxLinePattern(strings.TrimRight(line, " \t\r")); ok {
+			lines[i] = pattern
+			spdxExprs = append(spdxExprs, expr)
+			continue
+		}
+		parts := strings.Split(strings.TrimRight(line, " \t\r"), "{{YEAR}}")
+		for j, part := range parts {
+			parts[j] = regexp.QuoteMeta(part)
+		}
+		lines[i] = strings.Join(parts, `(?P<year>\d\d\d\d)`)
+	}
+	const lineEnd = `[ \t]*\r?\n`
 
 	return &licenserImpl{
+		template:         license,
 		newLicenseHeader: strings.Replace(license, "{{YEAR}}", strconv.Itoa(time.Now().Year()), -1),
-		matchRegexp:      regexp.MustCompile(`^` + strings.Join(parts, `\d\d\d\d`) + "\n"),
+		matchRegexp:      regexp.MustCompile(`^` + strings.Join(lines, lineEnd) + lineEnd),
+		spdxExprs:        spdxExprs,
 	}
 }
 
-func VerifyFiles(files []string, projectParam ProjectParam, stdout io.Writer) (bool, error) {
-	// run verify
-	modified, err := processFiles(files, projectParam, false, applyLicenseToFiles)
-	if err != nil {
-		return false, err
+func (l *licenserImpl) withCommentStyle(style CommentStyle) Licenser {
+	styled := NewLicenser(style.Render(headerText(l.template))).(*licenserImpl)
+	for _, accepted := range l.accepted {
+		styled.accepted = append(styled.accepted, NewLicenser(style.Render(headerText(accepted.template))).(*licenserImpl))
 	}
-	if len(modified) == 0 {
-		return true, nil
+	styled.convert = l.convert
+	styled.strict = l.strict
+	styled.thirdParty = l.thirdParty
+	styled.blankLinesAfter = l.blankLinesAfter
+	if l.headerRegexp != "" {
+		styled.setHeaderRegexp(l.headerRegexp, style)
 	}
+	return styled
+}
 
-	var plural string
-	if len(modified) == 1 {
-		plural = "file does"
-	} else {
-		plural = "files do"
+func (l *licenserImpl) withConvert() Licenser {
+	converted := *l
+	converted.convert = true
+	return &converted
+}
+
+// yearRegexp matches the years in a license header.
+var yearRegexp = regexp.MustCompile(`\b(19|20)\d\d\b`)
+
+func (l *licenserImpl) withAnyYear() Licenser {
+	anyYear := NewLicenser(yearRegexp.ReplaceAllString(l.template, "{{YEAR}}")).(*licenserImpl)
+	anyYear.convert = l.convert
+	return anyYear
+}
+
+func (l *licenserImpl) headerTemplate() string {
+	return l.template
+}
+
+func VerifyFiles(files []string, projectParam ProjectParam, stdout io.Writer) (bool, error) {
+	return VerifyFilesContext(context.Background(), files, projectParam, stdout)
+}
+
+// VerifyFS verifies the license headers of the provided files in the provided file system. The file paths are
+// interpreted as paths in fsys and are matched against the exclude and custom header configuration of projectParam in
+// the same manner as VerifyFiles. Files that do not have the correct header are printed to stdout.
+func VerifyFS(fsys fs.FS, files []string, projectParam ProjectParam, stdout io.Writer) (bool, error) {
+	result := make(verifyResult)
+	if _, err := processFiles(context.Background(), files, projectParam, false, func(ctx context.Context, files []string, licenser Licenser, modify bool, observer Observer) ([]string, error) {
+		return visitFS(fsys, files, observer, func(path string, content string) (bool, bool, error) {
+			matched, failed := result.verify(path, content, licenser)
+			return matched, failed, nil
+		})
+	}); err != nil {
+		return false, err
 	}
-	parts := append([]string{fmt.Sprintf("%d %s not have the correct license header:", len(modified), plural)}, modified...)
-	_, _ = fmt.Fprintln(stdout, strings.Join(parts, "\n\t"))
-	return false, nil
+	return result.print(stdout), nil
 }
 
 func LicenseFiles(files []string, projectParam ProjectParam) ([]string, error) {
-	return processFiles(files, projectParam, true, applyLicenseToFiles)
+	return LicenseFilesContext(context.Background(), files, projectParam)
 }
 
 func UnlicenseFiles(files []string, projectParam ProjectParam) ([]string, error) {
-	return processFiles(files, projectParam, true, removeLicenseFromFiles)
+	return UnlicenseFilesContext(context.Background(), files, projectParam)
 }
 
-func processFiles(files []string, projectParam ProjectParam, modify bool, f func(files []string, licenser Licenser, modify bool) ([]string, error)) ([]string, error) {
+// VerifyFilesContext is like VerifyFiles, but stops processing files when the provided context is done. If the context
+// is done before all of the files are verified, the files found so far that do not have the correct license header
+// are printed and the error of the context is returned.
+func VerifyFilesContext(ctx context.Context, files []string, projectParam ProjectParam, stdout io.Writer) (bool, error) {
+	result := make(verifyResult)
+	_, err := processFiles(ctx, files, projectParam, false, func(ctx context.Context, files []string, licenser Licenser, modify bool, observer Observer) ([]string, error) {
+		return visitFiles(ctx, files, observer, func(path string, fi os.FileInfo, content string) (bool, bool, error) {
+			matched, failed := result.verify(path, content, licenser)
+			return matched, failed, nil
+		})
+	})
+	if err != nil && ctx.Err() == nil {
+		return false, err
+	}
+	ok := result.print(stdout)
+	if err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// LicenseFilesContext is like LicenseFiles, but stops processing files when the provided context is done. If the
+// context is done before all of the files are processed, the files modified so far are returned along with the error
+// of the context. Files are written atomically, so a cancelled operation never leaves a partially written file.
+func LicenseFilesContext(ctx context.Context, files []string, projectParam ProjectParam) ([]string, error) {
+	failures := newParseCheckFailures(projectParam.ParseCheck)
+	modified, err := processFiles(ctx, files, projectParam, true, func(ctx context.Context, files []string, licenser Licenser, modify bool, observer Observer) ([]string, error) {
+		return applyLicenseToFiles(ctx, files, licenser, modify, failures, observer)
+	})
+	if err != nil {
+		return modified, err
+	}
+	return modified, failures.err()
+}
+
+// UnlicenseFilesContext is like UnlicenseFiles, but stops processing files when the provided context is done. If the
+// context is done before all of the files are processed, the files modified so far are returned along with the error
+// of the context. Files are written atomically, so a cancelled operation never leaves a partially written file.
+func UnlicenseFilesContext(ctx context.Context, files []string, projectParam ProjectParam) ([]string, error) {
+	failures := newParseCheckFailures(projectParam.ParseCheck)
+	modified, err := processFiles(ctx, files, projectParam, true, func(ctx context.Context, files []string, licenser Licenser, modify bool, observer Observer) ([]string, error) {
+		return removeLicenseFromFiles(ctx, files, licenser, modify, failures, observer)
+	})
+	if err != nil {
+		return modified, err
+	}
+	return modified, failures.err()
+}
+
+// processFiles processes the provided files using f. If the provided context is done before all of the files are
+// processed, the files modified so far are returned along with the error of the context.
+func processFiles(ctx context.Context, files []string, projectParam ProjectParam, modify bool, f func(ctx context.Context, files []string, licenser Licenser, modify bool, observer Observer) ([]string, error)) ([]string, error) {
 	// if header and matchers do not exist, return (nothing to check)
 	if projectParam.Licenser.Empty() && len(projectParam.CustomHeaders) == 0 {
 		return nil, nil
 	}
 
-	goFileMatcher := matcher.Name(`.*\.go`)
-	var goFiles []string
+	var includedFiles []string
 	for _, f := range files {
-		if goFileMatcher.Match(f) && (projectParam.Exclude == nil || !projectParam.Exclude.Match(f)) {
-			goFiles = append(goFiles, f)
+		if isIncluded(f, projectParam) {
+			includedFiles = append(includedFiles, f)
+		} else {
+			projectParam.Observer.notify(Event{Type: EventFileExcluded, Path: f})
 		}
 	}
 
 	// name of custom matcher -> files to process for the matcher
 	m := make(map[string][]string)
-	for _, f := range goFiles {
-		var longestMatcher string
-		longestMatchLen := 0
-		for _, v := range projectParam.CustomHeaders {
-			for _, p := range v.IncludePaths {
-				if matcher.PathLiteral(p).Match(f) && len(p) >= longestMatchLen {
-					longestMatcher = v.Name
-					longestMatchLen = len(p)
-				}
-			}
+	for _, f := range includedFiles {
+		if customHeader, ok := customHeaderForFile(f, projectParam.CustomHeaders); ok {
+			m[customHeader.Name] = append(m[customHeader.Name], f)
+			projectParam.Observer.notify(Event{Type: EventFileSelected, Path: f, HeaderName: customHeader.Name})
+		} else {
+			projectParam.Observer.notify(Event{Type: EventFileSelected, Path: f})
 		}
-		// file may match multiple custom header params -- if that is the case, use the longest match. Allows
-		// for hierarchical matching.
-		if longestMatcher != "" {
-			m[longestMatcher] = append(m[longestMatcher], f)
+	}
+
+	// processWithLicenser processes the provided files using the header of the provided Licenser rendered in the
+	// comment style of the type of each file
+	processWithLicenser := func(files []string, licenser Licenser, observer Observer) ([]string, error) {
+		var currModified []string
+		for _, group := range groupByFileType(files, projectParam) {
+			groupModified, err := f(ctx, group.files, licenserForFileType(licenser, group.fileType, projectParam), modify, observer)
+			currModified = append(currModified, groupModified...)
+			if err != nil {
+				return currModified, errors.Wrapf(err, "failed to process %s files", group.fileType.Name)
+			}
 		}
+		return currModified, nil
 	}
 
 	// all files that were processed (considered by a matcher)
@@ -170,28 +533,36 @@ func processFiles(files []string, projectParam ProjectParam, modify bool, f func
 
 	// process custom matchers
 	for _, v := range projectParam.CustomHeaders {
-		currModified, err := f(m[v.Name], v.Licenser, modify)
+		currModified, err := processWithLicenser(m[v.Name], v.configuredLicenser(), projectParam.Observer.withHeaderName(v.Name))
+		modified = append(modified, currModified...)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				sort.Strings(modified)
+				return modified, ctxErr
+			}
 			return nil, errors.Wrapf(err, "failed to process headers for matcher %s", v.Name)
 		}
-		modified = append(modified, currModified...)
 		for _, f := range m[v.Name] {
 			processedFiles[f] = struct{}{}
 		}
 	}
 
-	// process all "*.go" files not matched by custom matchers
-	var unprocessedGoFiles []string
-	for _, f := range goFiles {
+	// process all files not matched by custom matchers
+	var unprocessedFiles []string
+	for _, f := range includedFiles {
 		if _, ok := processedFiles[f]; !ok {
-			unprocessedGoFiles = append(unprocessedGoFiles, f)
+			unprocessedFiles = append(unprocessedFiles, f)
 		}
 	}
-	currModified, err := f(unprocessedGoFiles, projectParam.Licenser, modify)
+	currModified, err := processWithLicenser(unprocessedFiles, projectParam.configuredLicenser(), projectParam.Observer)
+	modified = append(modified, currModified...)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to process headers for default *.go matcher")
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			sort.Strings(modified)
+			return modified, ctxErr
+		}
+		return nil, errors.Wrapf(err, "failed to process headers for default matcher")
 	}
-	modified = append(modified, currModified...)
 	for _, f := range currModified {
 		processedFiles[f] = struct{}{}
 	}
@@ -200,52 +571,174 @@ func processFiles(files []string, projectParam ProjectParam, modify bool, f func
 	return modified, nil
 }
 
-func applyLicenseToFiles(files []string, licenser Licenser, modify bool) ([]string, error) {
-	return visitFiles(files, func(path string, fi os.FileInfo, content string) (bool, error) {
-		if !licenser.Matches(content) {
-			if modify {
-				content = licenser.Add(content)
-				if err := os.WriteFile(path, []byte(content), fi.Mode()); err != nil {
-					return false, errors.Wrapf(err, "failed to write file %s with new license", path)
-				}
+// isIncluded returns true if the provided file is of a file type that is processed and is not excluded by
+// projectParam.
+func isIncluded(file string, projectParam ProjectParam) bool {
+	_, ok := fileTypeForFile(file, projectParam)
+	return ok && (projectParam.Exclude == nil || !projectParam.Exclude.Match(file))
+}
+
+type fileTypeGroup struct {
+	fileType FileTypeParam
+	files    []string
+}
+
+// groupByFileType groups the provided files by their file type. The groups are ordered in the same manner as the file
+// types are matched: the Go file type first, followed by the file types of projectParam in order. The provided files
+// must all be of a file type that is processed.
+func groupByFileType(files []string, projectParam ProjectParam) []fileTypeGroup {
+	var groups []fileTypeGroup
+	groupIdx := make(map[string]int)
+	for _, fileType := range append([]FileTypeParam{goFileType}, projectParam.FileTypes...) {
+		if _, ok := groupIdx[fileType.Name]; !ok {
+			groupIdx[fileType.Name] = len(groups)
+			groups = append(groups, fileTypeGroup{fileType: fileType})
+		}
+	}
+	for _, f := range files {
+		fileType, _ := fileTypeForFile(f, projectParam)
+		idx := groupIdx[fileType.Name]
+		groups[idx].files = append(groups[idx].files, f)
+	}
+
+	var nonEmptyGroups []fileTypeGroup
+	for _, group := range groups {
+		if len(group.files) > 0 {
+			nonEmptyGroups = append(nonEmptyGroups, group)
+		}
+	}
+	return nonEmptyGroups
+}
+
+// customHeaderForFile returns the custom header parameter that applies to the provided file. A file may match multiple
+// custom header params -- if that is the case, the longest match is used, which allows for hierarchical matching.
+// Returns false if no custom header parameter applies to the file.
+func customHeaderForFile(file string, customHeaders []CustomHeaderParam) (CustomHeaderParam, bool) {
+	var longestMatcher CustomHeaderParam
+	longestMatchLen := 0
+	found := false
+	for _, v := range customHeaders {
+		for _, p := range v.IncludePaths {
+			if matcher.PathLiteral(p).Match(file) && len(p) >= longestMatchLen {
+				longestMatcher = v
+				longestMatchLen = len(p)
+				found = true
 			}
-			return true, nil
 		}
-		return false, nil
+	}
+	return longestMatcher, found
+}
+
+func applyLicenseToFiles(ctx context.Context, files []string, licenser Licenser, modify bool, failures *parseCheckFailures, observer Observer) ([]string, error) {
+	return visitFiles(ctx, files, observer, func(path string, fi os.FileInfo, content string) (bool, bool, error) {
+		if copyright, ok := thirdPartyCode(licenser, content); ok {
+			return false, false, errors.Errorf("%s is third-party code (%s)", path, copyright)
+		}
+		newContent, matched := addLicense(path, licenser, content)
+		changed := newContent != content
+		if changed && modify {
+			written, err := writeModifiedFile(path, content, newContent, fi.Mode(), failures, observer)
+			if err != nil {
+				return matched, false, errors.Wrapf(err, "failed to write file %s with new license", path)
+			}
+			changed = written
+		}
+		return matched, changed, nil
 	})
 }
 
-func removeLicenseFromFiles(files []string, licenser Licenser, modify bool) ([]string, error) {
-	return visitFiles(files, func(path string, fi os.FileInfo, content string) (bool, error) {
-		if licenser.Matches(content) {
-			if modify {
-				content = licenser.Remove(content)
-				if err := os.WriteFile(path, []byte(content), fi.Mode()); err != nil {
-					return false, errors.Wrapf(err, "failed to write file %s with license removed", path)
-				}
+func removeLicenseFromFiles(ctx context.Context, files []string, licenser Licenser, modify bool, failures *parseCheckFailures, observer Observer) ([]string, error) {
+	return visitFiles(ctx, files, observer, func(path string, fi os.FileInfo, content string) (bool, bool, error) {
+		newContent, matched := removeLicense(licenser, content)
+		changed := newContent != content
+		if changed && modify {
+			written, err := writeModifiedFile(path, content, newContent, fi.Mode(), failures, observer)
+			if err != nil {
+				return matched, false, errors.Wrapf(err, "failed to write file %s with license removed", path)
 			}
-			return true, nil
+			changed = written
 		}
-		return false, nil
+		return matched, changed, nil
 	})
 }
 
-func visitFiles(files []string, visitor func(path string, fi os.FileInfo, content string) (bool, error)) ([]string, error) {
+// addLicense returns the provided content with the license of the licenser added and whether the provided content
+// matched the license. Content that already has the license is returned unmodified unless it has the license more than
+// once, in which case the repeated licenses are collapsed into a single license, the Licenser enforces a different
+// number of blank lines after the license than the content has, or the content is a Go file in which the license is
+// part of the package documentation, in which case a blank line is inserted after the license.
+func addLicense(path string, licenser Licenser, content string) (string, bool) {
+	if licenser.Matches(content) {
+		return separatePackageDoc(path, normalizeBlankLines(licenser, collapseDuplicateLicense(licenser, content)), licenser), true
+	}
+	return licenser.Add(content), false
+}
+
+// removeLicense returns the provided content with the license of the licenser removed and whether the provided content
+// matched the license. Content that does not have the license is returned unmodified.
+func removeLicense(licenser Licenser, content string) (string, bool) {
+	if !licenser.Matches(content) {
+		return content, false
+	}
+	return licenser.Remove(content), true
+}
+
+// visitFiles calls the visitor for each of the provided files and returns the files for which the visitor returned
+// true for changed. The visitor returns whether the content of the file matched the license and whether the file was
+// (or would have been) changed, and the corresponding events are sent to the observer. The context is checked before
+// each file is visited: if it is done, the files modified so far are returned along with the error of the context.
+func visitFiles(ctx context.Context, files []string, observer Observer, visitor func(path string, fi os.FileInfo, content string) (matched, changed bool, err error)) ([]string, error) {
 	var modified []string
 
 	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return modified, err
+		}
 		fi, err := os.Stat(f)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to stat %s", f)
+			err = errors.Wrapf(err, "failed to stat %s", f)
+			observer.notify(Event{Type: EventError, Path: f, Err: err})
+			return nil, err
 		}
 		bytes, err := os.ReadFile(f)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to read %s", f)
+			err = errors.Wrapf(err, "failed to read %s", f)
+			observer.notify(Event{Type: EventError, Path: f, Err: err})
+			return nil, err
 		}
 		content := string(bytes)
-		if changed, err := visitor(f, fi, content); err != nil {
+		matched, changed, err := visitor(f, fi, content)
+		if err != nil {
+			observer.notify(Event{Type: EventError, Path: f, Err: err})
+			return nil, errors.WithStack(err)
+		}
+		notifyResult(observer, f, matched, changed)
+		if changed {
+			modified = append(modified, f)
+		}
+	}
+
+	return modified, nil
+}
+
+// visitFS is like visitFiles, but reads the files from the provided file system.
+func visitFS(fsys fs.FS, files []string, observer Observer, visitor func(path string, content string) (matched, changed bool, err error)) ([]string, error) {
+	var modified []string
+
+	for _, f := range files {
+		bytes, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to read %s", f)
+			observer.notify(Event{Type: EventError, Path: f, Err: err})
+			return nil, err
+		}
+		matched, changed, err := visitor(f, string(bytes))
+		if err != nil {
+			observer.notify(Event{Type: EventError, Path: f, Err: err})
 			return nil, errors.WithStack(err)
-		} else if changed {
+		}
+		notifyResult(observer, f, matched, changed)
+		if changed {
 			modified = append(modified, f)
 		}
 	}
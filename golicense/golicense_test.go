@@ -5,11 +5,15 @@
 package golicense_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/palantir/go-license/golicense"
@@ -43,8 +47,10 @@ package bar`,
 			},
 			wantContent: map[string]string{
 				"foo.go": `// Copyright 2016 Palantir Technologies, Inc.
+
 package foo`,
 				"bar/bar.go": `// Copyright 2016 Palantir Technologies, Inc.
+
 // Original comment
 package bar`,
 			},
@@ -65,8 +71,10 @@ package bar`,
 			},
 			wantContent: map[string]string{
 				"foo.go": fmt.Sprintf(`// Copyright %d Palantir Technologies, Inc.
+
 package foo`, time.Now().Year()),
 				"bar/bar.go": fmt.Sprintf(`// Copyright %d Palantir Technologies, Inc.
+
 // Original comment
 package bar`, time.Now().Year()),
 			},
@@ -100,6 +108,7 @@ package bar`,
 			wantContent: map[string]string{
 				"foo.go": `package foo`,
 				"bar/bar.go": `// Copyright 2016 Palantir Technologies, Inc.
+
 // Original comment
 package bar`,
 			},
@@ -112,16 +121,45 @@ package bar`,
 			files: map[string]string{
 				"foo.go": `package foo`,
 				"bar/bar.go": `// Copyright 2016 Palantir Technologies, Inc.
+
+// Original comment
+package bar`,
+			},
+			wantModified: []string{
+				"foo.go",
+			},
+			wantContent: map[string]string{
+				"foo.go": `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+				"bar/bar.go": `// Copyright 2016 Palantir Technologies, Inc.
+
+// Original comment
+package bar`,
+			},
+		},
+		{
+			name: "blank line added after existing license that is package documentation",
+			projectParam: golicense.ProjectParam{
+				Licenser: golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+			},
+			files: map[string]string{
+				"foo.go": `// Copyright 2016 Palantir Technologies, Inc.
+package foo`,
+				"bar/bar.go": `// Copyright 2016 Palantir Technologies, Inc.
 // Original comment
 package bar`,
 			},
 			wantModified: []string{
+				"bar/bar.go",
 				"foo.go",
 			},
 			wantContent: map[string]string{
 				"foo.go": `// Copyright 2016 Palantir Technologies, Inc.
+
 package foo`,
 				"bar/bar.go": `// Copyright 2016 Palantir Technologies, Inc.
+
 // Original comment
 package bar`,
 			},
@@ -155,10 +193,13 @@ package bar`,
 			},
 			wantContent: map[string]string{
 				"foo.go": `// Copyright 2016 Palantir Technologies, Inc.
+
 package foo`,
 				"bar/bar.go": `// Copyright 2016 Custom Co.
+
 package bar`,
 				"baz/baz.go": `// Copyright 2006 Legacy Inc.
+
 package baz`,
 			},
 		},
@@ -196,15 +237,77 @@ package baz`,
 			},
 			wantContent: map[string]string{
 				"foo.go": `// Copyright 2016 Palantir Technologies, Inc.
+
 package foo`,
 				"bar/bar.go": `// Copyright 2016 Custom Co.
+
 package bar`,
 				"bar/baz.go": `// Copyright 2006 Legacy Inc.
+
 package bar`,
 				"bar/subdir/main.go": `// Copyright 2006 Legacy Inc.
+
 package main`,
 			},
 		},
+		{
+			name: "license rendered in comment style of file type",
+			projectParam: golicense.ProjectParam{
+				Licenser: golicense.NewLicenser("// Copyright {{YEAR}} Palantir Technologies, Inc.\n//\n// License content.\n"),
+				CustomHeaders: []golicense.CustomHeaderParam{
+					{
+						Name:         "Custom Co.",
+						Licenser:     golicense.NewLicenser("// Copyright 2016 Custom Co.\n"),
+						IncludePaths: []string{"custom"},
+					},
+				},
+				FileTypes: []golicense.FileTypeParam{
+					{
+						Name:         "shell",
+						Matcher:      matcher.Name(`.*\.sh`, "Dockerfile"),
+						CommentStyle: golicense.HashStyle,
+					},
+					{
+						Name:         "sql",
+						Matcher:      matcher.Name(`.*\.sql`),
+						CommentStyle: golicense.DashStyle,
+					},
+					{
+						Name:         "javascript",
+						Matcher:      matcher.Name(`.*\.js`),
+						CommentStyle: golicense.BlockStyle,
+					},
+				},
+			},
+			files: map[string]string{
+				"foo.go":            "package foo\n",
+				"run.sh":            "echo foo\n",
+				"Dockerfile":        "FROM scratch\n",
+				"schema.sql":        "SELECT 1;\n",
+				"app.js":            "foo();\n",
+				"custom/run.sh":     "echo bar\n",
+				"licensed.sh":       "# Copyright 2016 Palantir Technologies, Inc.\n#\n# License content.\n\necho baz\n",
+				"not-processed.txt": "foo\n",
+			},
+			wantModified: []string{
+				"Dockerfile",
+				"app.js",
+				"custom/run.sh",
+				"foo.go",
+				"run.sh",
+				"schema.sql",
+			},
+			wantContent: map[string]string{
+				"foo.go":            fmt.Sprintf("// Copyright %d Palantir Technologies, Inc.\n//\n// License content.\n\npackage foo\n", time.Now().Year()),
+				"run.sh":            fmt.Sprintf("# Copyright %d Palantir Technologies, Inc.\n#\n# License content.\n\necho foo\n", time.Now().Year()),
+				"Dockerfile":        fmt.Sprintf("# Copyright %d Palantir Technologies, Inc.\n#\n# License content.\n\nFROM scratch\n", time.Now().Year()),
+				"schema.sql":        fmt.Sprintf("-- Copyright %d Palantir Technologies, Inc.\n--\n-- License content.\n\nSELECT 1;\n", time.Now().Year()),
+				"app.js":            fmt.Sprintf("/*\nCopyright %d Palantir Technologies, Inc.\n\nLicense content.\n*/\n\nfoo();\n", time.Now().Year()),
+				"custom/run.sh":     "# Copyright 2016 Custom Co.\n\necho bar\n",
+				"licensed.sh":       "# Copyright 2016 Palantir Technologies, Inc.\n#\n# License content.\n\necho baz\n",
+				"not-processed.txt": "foo\n",
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			tmpDir := t.TempDir()
@@ -390,6 +493,537 @@ package baz`,
 	}
 }
 
+func TestLicenserProlog(t *testing.T) {
+	licenser := golicense.NewLicenser("# Copyright {{YEAR}} Palantir Technologies, Inc.\n")
+	year := time.Now().Year()
+	for _, tc := range []struct {
+		name      string
+		content   string
+		wantAdded string
+	}{
+		{
+			name:      "interpreter directive",
+			content:   "#!/bin/bash\necho foo\n",
+			wantAdded: fmt.Sprintf("#!/bin/bash\n# Copyright %d Palantir Technologies, Inc.\n\necho foo\n", year),
+		},
+		{
+			name:      "XML declaration",
+			content:   "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<foo/>\n",
+			wantAdded: fmt.Sprintf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n# Copyright %d Palantir Technologies, Inc.\n\n<foo/>\n", year),
+		},
+		{
+			name:      "encoding declaration",
+			content:   "# -*- coding: utf-8 -*-\nprint('foo')\n",
+			wantAdded: fmt.Sprintf("# -*- coding: utf-8 -*-\n# Copyright %d Palantir Technologies, Inc.\n\nprint('foo')\n", year),
+		},
+		{
+			name:      "interpreter directive followed by encoding declaration",
+			content:   "#!/usr/bin/env python\n# -*- coding: utf-8 -*-\nprint('foo')\n",
+			wantAdded: fmt.Sprintf("#!/usr/bin/env python\n# -*- coding: utf-8 -*-\n# Copyright %d Palantir Technologies, Inc.\n\nprint('foo')\n", year),
+		},
+		{
+			name:      "byte order mark",
+			content:   "\ufeffecho foo\n",
+			wantAdded: fmt.Sprintf("\ufeff# Copyright %d Palantir Technologies, Inc.\n\necho foo\n", year),
+		},
+		{
+			name:      "byte order mark followed by interpreter directive",
+			content:   "\ufeff#!/bin/bash\necho foo\n",
+			wantAdded: fmt.Sprintf("\ufeff#!/bin/bash\n# Copyright %d Palantir Technologies, Inc.\n\necho foo\n", year),
+		},
+		{
+			name:      "interpreter directive only on first line",
+			content:   "echo foo\n#!/bin/bash\n",
+			wantAdded: fmt.Sprintf("# Copyright %d Palantir Technologies, Inc.\n\necho foo\n#!/bin/bash\n", year),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.False(t, licenser.Matches(tc.content))
+			added := licenser.Add(tc.content)
+			assert.Equal(t, tc.wantAdded, added)
+			assert.True(t, licenser.Matches(added))
+			assert.Equal(t, tc.content, licenser.Remove(added))
+		})
+	}
+}
+
+func TestLicenserCommentStyles(t *testing.T) {
+	lineHeader := "// Copyright 2016 Palantir Technologies, Inc.\n// License content.\n"
+	blockHeader := "/*\nCopyright 2016 Palantir Technologies, Inc.\nLicense content.\n*/\n"
+
+	for _, tc := range []struct {
+		name   string
+		header string
+		other  string
+	}{
+		{"line header matches block rendering", lineHeader, blockHeader},
+		{"block header matches line rendering", blockHeader, lineHeader},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			licenser := golicense.NewLicenser(tc.header)
+			content := tc.other + "\npackage foo\n"
+			assert.True(t, licenser.Matches(content))
+			assert.Equal(t, "package foo\n", licenser.Remove(content))
+			assert.Equal(t, tc.header+"\npackage foo\n", licenser.Add("package foo\n"))
+		})
+	}
+
+	t.Run("convert replaces other rendering", func(t *testing.T) {
+		projectParam := golicense.ProjectParam{
+			Licenser:            golicense.NewLicenser(lineHeader),
+			ConvertCommentStyle: true,
+		}
+		content := []byte(blockHeader + "\npackage foo\n")
+
+		got, status := golicense.LicenseContent("foo.go", content, projectParam)
+		assert.Equal(t, golicense.StatusModified, status)
+		assert.Equal(t, lineHeader+"\npackage foo\n", string(got))
+
+		got, status = golicense.LicenseContent("foo.go", got, projectParam)
+		assert.Equal(t, golicense.StatusUnchanged, status)
+		assert.Equal(t, lineHeader+"\npackage foo\n", string(got))
+
+		projectParam.ConvertCommentStyle = false
+		_, status = golicense.LicenseContent("foo.go", content, projectParam)
+		assert.Equal(t, golicense.StatusUnchanged, status)
+	})
+}
+
+func TestLicenserSPDX(t *testing.T) {
+	text, err := golicense.SPDXHeader("apache-2.0 OR MIT", "Palantir Technologies, Inc.")
+	require.NoError(t, err)
+	assert.Equal(t, "Copyright {{YEAR}} Palantir Technologies, Inc.\nSPDX-License-Identifier: Apache-2.0 OR MIT", text)
+
+	licenser := golicense.NewLicenser(golicense.SlashStyle.Render(text))
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"same expression matches", "// Copyright 2016 Palantir Technologies, Inc.\n// SPDX-License-Identifier: Apache-2.0 OR MIT\n\npackage foo\n", true},
+		{"equivalent expression matches", "// Copyright 2016 Palantir Technologies, Inc.\n// SPDX-License-Identifier: (mit OR Apache-2.0)\n\npackage foo\n", true},
+		{"equivalent expression in block rendering matches", "/*\nCopyright 2016 Palantir Technologies, Inc.\nSPDX-License-Identifier: MIT OR Apache-2.0\n*/\n\npackage foo\n", true},
+		{"different expression does not match", "// Copyright 2016 Palantir Technologies, Inc.\n// SPDX-License-Identifier: Apache-2.0 AND MIT\n\npackage foo\n", false},
+		{"invalid expression does not match", "// Copyright 2016 Palantir Technologies, Inc.\n// SPDX-License-Identifier: Apache-2.0 OR\n\npackage foo\n", false},
+		{"different holder does not match", "// Copyright 2016 Other Co.\n// SPDX-License-Identifier: Apache-2.0 OR MIT\n\npackage foo\n", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, licenser.Matches(tc.content))
+			if tc.want {
+				assert.Equal(t, "package foo\n", licenser.Remove(tc.content))
+			}
+		})
+	}
+
+	assert.Equal(t, fmt.Sprintf("// Copyright %d Palantir Technologies, Inc.\n// SPDX-License-Identifier: Apache-2.0 OR MIT\n\npackage foo\n", time.Now().Year()), licenser.Add("package foo\n"))
+
+	_, err = golicense.SPDXHeader("Apache-2.0 OR Foo", "")
+	assert.EqualError(t, err, `invalid SPDX license expression "Apache-2.0 OR Foo": unknown license identifier "Foo"`)
+}
+
+func TestLicenserLineEndings(t *testing.T) {
+	licenser := golicense.NewLicenser("// Copyright {{YEAR}} Palantir Technologies, Inc.\n//\n// License content.\n")
+	year := time.Now().Year()
+
+	for _, tc := range []struct {
+		name        string
+		content     string
+		wantMatches bool
+		wantRemoved string
+	}{
+		{
+			name:        "CRLF line endings",
+			content:     "// Copyright 2016 Palantir Technologies, Inc.\r\n//\r\n// License content.\r\n\r\npackage foo\r\n",
+			wantMatches: true,
+			wantRemoved: "package foo\r\n",
+		},
+		{
+			name:        "trailing whitespace",
+			content:     "// Copyright 2016 Palantir Technologies, Inc. \n// \t\n// License content.\n\t\npackage foo\n",
+			wantMatches: true,
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:    "different content",
+			content: "// Copyright 2016 Palantir Technologies, Inc.\r\n// Other content.\r\n\r\npackage foo\r\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantMatches, licenser.Matches(tc.content))
+			if tc.wantMatches {
+				assert.Equal(t, tc.wantRemoved, licenser.Remove(tc.content))
+			}
+		})
+	}
+
+	t.Run("add preserves CRLF line endings", func(t *testing.T) {
+		added := licenser.Add("package foo\r\n\r\nfunc Foo() {}\r\n")
+		assert.Equal(t, fmt.Sprintf("// Copyright %d Palantir Technologies, Inc.\r\n//\r\n// License content.\r\n\r\npackage foo\r\n\r\nfunc Foo() {}\r\n", year), added)
+		assert.True(t, licenser.Matches(added))
+	})
+
+	t.Run("add preserves LF line endings", func(t *testing.T) {
+		added := licenser.Add("package foo\n")
+		assert.Equal(t, fmt.Sprintf("// Copyright %d Palantir Technologies, Inc.\n//\n// License content.\n\npackage foo\n", year), added)
+	})
+}
+
+func TestLicenserByteOrderMark(t *testing.T) {
+	licenser := golicense.NewLicenser("// Copyright 2016 Palantir Technologies, Inc.\n")
+	content := "\ufeff// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo\n"
+	assert.True(t, licenser.Matches(content))
+	assert.Equal(t, "\ufeffpackage foo\n", licenser.Remove(content))
+	assert.Equal(t, content, licenser.Add("\ufeffpackage foo\n"))
+}
+
+func TestLicenseFilesContext(t *testing.T) {
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+	}
+
+	t.Run("cancelled context stops processing", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		files := writeFiles(t, tmpDir, map[string]string{
+			"foo.go": `package foo`,
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		modified, err := golicense.LicenseFilesContext(ctx, files, projectParam)
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, modified)
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, "foo.go"))
+		require.NoError(t, err)
+		assert.Equal(t, `package foo`, string(content))
+
+		buf := &bytes.Buffer{}
+		ok, err := golicense.VerifyFilesContext(ctx, files, projectParam, buf)
+		assert.Equal(t, context.Canceled, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("files written atomically with original mode", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		files := writeFiles(t, tmpDir, map[string]string{
+			"foo.go": `package foo`,
+		})
+		err := os.Chmod(filepath.Join(tmpDir, "foo.go"), 0640)
+		require.NoError(t, err)
+
+		modified, err := golicense.LicenseFilesContext(context.Background(), files, projectParam)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo.go"}, modified)
+
+		fi, err := os.Stat(filepath.Join(tmpDir, "foo.go"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+
+		entries, err := os.ReadDir(tmpDir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "foo.go", entries[0].Name())
+	})
+
+	t.Run("file that symbolic link refers to written", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		writeFiles(t, tmpDir, map[string]string{
+			"real.go": `package foo`,
+		})
+		require.NoError(t, os.Symlink("real.go", "link.go"))
+
+		modified, err := golicense.LicenseFiles([]string{"link.go"}, projectParam)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"link.go"}, modified)
+
+		target, err := os.Readlink("link.go")
+		require.NoError(t, err)
+		assert.Equal(t, "real.go", target)
+		content, err := os.ReadFile("real.go")
+		require.NoError(t, err)
+		assert.Equal(t, "// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo", string(content))
+	})
+
+	t.Run("file with other hard links written in place", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		writeFiles(t, tmpDir, map[string]string{
+			"foo.go": `package foo`,
+		})
+		require.NoError(t, os.Link("foo.go", "bar.txt"))
+
+		_, err := golicense.LicenseFiles([]string{"foo.go"}, projectParam)
+		require.NoError(t, err)
+		content, err := os.ReadFile("bar.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo", string(content))
+	})
+
+	t.Run("file in read-only directory written in place", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("directory permissions do not apply to root")
+		}
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		writeFiles(t, tmpDir, map[string]string{
+			"dir/foo.go": `package foo`,
+		})
+		require.NoError(t, os.Chmod("dir", 0555))
+		defer func() {
+			_ = os.Chmod("dir", 0755)
+		}()
+
+		_, err := golicense.LicenseFiles([]string{"dir/foo.go"}, projectParam)
+		require.NoError(t, err)
+		content, err := os.ReadFile("dir/foo.go")
+		require.NoError(t, err)
+		assert.Equal(t, "// Copyright 2016 Palantir Technologies, Inc.\n\npackage foo", string(content))
+	})
+}
+
+func TestLicenseFilesParseCheck(t *testing.T) {
+	blockLicenser := golicense.NewLicenser("/*\nCopyright 2016 Palantir Technologies, Inc.\n*/")
+
+	for _, tc := range []struct {
+		name        string
+		licenser    golicense.Licenser
+		parseCheck  bool
+		content     string
+		wantContent string
+		wantErr     string
+	}{
+		{
+			name:        "valid change written",
+			licenser:    golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+			parseCheck:  true,
+			content:     "//go:build linux\n\npackage foo\n",
+			wantContent: "// Copyright 2016 Palantir Technologies, Inc.\n\n//go:build linux\n\npackage foo\n",
+		},
+		{
+			name:        "change that disables build constraints not written",
+			licenser:    blockLicenser,
+			parseCheck:  true,
+			content:     "// +build linux\n\npackage foo\n",
+			wantContent: "// +build linux\n\npackage foo\n",
+			wantErr:     "1 file failed the parse check and was not modified:\n\tfoo.go: build constraints changed from [// +build linux] to []",
+		},
+		{
+			name:        "change that disables build constraints written without parse check",
+			licenser:    blockLicenser,
+			content:     "// +build linux\n\npackage foo\n",
+			wantContent: "/*\nCopyright 2016 Palantir Technologies, Inc.\n*/\n\n// +build linux\n\npackage foo\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			oldWd := chdir(t, tmpDir)
+			defer oldWd()
+
+			files := writeFiles(t, tmpDir, map[string]string{
+				"foo.go": tc.content,
+			})
+			_, err := golicense.LicenseFiles(files, golicense.ProjectParam{
+				Licenser:   tc.licenser,
+				ParseCheck: tc.parseCheck,
+			})
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+			}
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, "foo.go"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantContent, string(content))
+		})
+	}
+
+	t.Run("files after a failed check still processed", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		writeFiles(t, tmpDir, map[string]string{
+			"a.go": "package foo\n",
+			"b.go": "// +build linux\n\npackage foo\n",
+			"c.go": "package foo\n",
+			"d.go": "// +build linux\n\npackage foo\n",
+		})
+		modified, err := golicense.LicenseFiles([]string{"a.go", "b.go", "c.go", "d.go"}, golicense.ProjectParam{
+			Licenser:   blockLicenser,
+			ParseCheck: true,
+		})
+		assert.EqualError(t, err, "2 files failed the parse check and were not modified:\n\tb.go: build constraints changed from [// +build linux] to []\n\td.go: build constraints changed from [// +build linux] to []")
+		assert.Equal(t, []string{"a.go", "c.go"}, modified)
+
+		for path, want := range map[string]string{
+			"a.go": "/*\nCopyright 2016 Palantir Technologies, Inc.\n*/\n\npackage foo\n",
+			"b.go": "// +build linux\n\npackage foo\n",
+			"c.go": "/*\nCopyright 2016 Palantir Technologies, Inc.\n*/\n\npackage foo\n",
+			"d.go": "// +build linux\n\npackage foo\n",
+		} {
+			content, err := os.ReadFile(filepath.Join(tmpDir, path))
+			require.NoError(t, err)
+			assert.Equal(t, want, string(content), path)
+		}
+	})
+}
+
+func TestVerifyFS(t *testing.T) {
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+		CustomHeaders: []golicense.CustomHeaderParam{
+			{
+				Name:         "Custom Co.",
+				Licenser:     golicense.NewLicenser("// Copyright 2016 Custom Co."),
+				IncludePaths: []string{"bar"},
+			},
+		},
+		Exclude: matcher.Name("excluded.go"),
+	}
+	fsys := fstest.MapFS{
+		"foo.go": &fstest.MapFile{Data: []byte(`// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`)},
+		"missing.go":  &fstest.MapFile{Data: []byte(`package foo`)},
+		"excluded.go": &fstest.MapFile{Data: []byte(`package foo`)},
+		"foo.txt":     &fstest.MapFile{Data: []byte(`foo`)},
+		"bar/bar.go": &fstest.MapFile{Data: []byte(`// Copyright 2016 Custom Co.
+
+package bar`)},
+		"bar/wrong.go": &fstest.MapFile{Data: []byte(`// Copyright 2016 Palantir Technologies, Inc.
+
+package bar`)},
+		"doc.go": &fstest.MapFile{Data: []byte(`// Copyright 2016 Palantir Technologies, Inc.
+// Package foo does things.
+package foo`)},
+	}
+
+	buf := &bytes.Buffer{}
+	ok, err := golicense.VerifyFS(fsys, []string{"foo.go", "missing.go", "excluded.go", "foo.txt", "bar/bar.go", "bar/wrong.go", "doc.go"}, projectParam, buf)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "2 files do not have the correct license header:\n\tbar/wrong.go (copyright of another holder: Copyright 2016 Palantir Technologies, Inc.)\n\tmissing.go (no license header)\n"+
+		"1 file has a license header that is treated as package documentation (add a blank line after it):\n\tdoc.go\n", buf.String())
+
+	buf.Reset()
+	ok, err = golicense.VerifyFS(fsys, []string{"foo.go", "bar/bar.go"}, projectParam, buf)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "", buf.String())
+}
+
+func TestRunLicenseStdin(t *testing.T) {
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+		CustomHeaders: []golicense.CustomHeaderParam{
+			{
+				Name:         "Custom Co.",
+				Licenser:     golicense.NewLicenser("// Copyright 2016 Custom Co."),
+				IncludePaths: []string{"bar"},
+			},
+		},
+		Exclude: matcher.Name("excluded.go"),
+	}
+
+	for _, tc := range []struct {
+		name       string
+		path       string
+		verify     bool
+		remove     bool
+		content    string
+		wantOutput string
+		wantErr    bool
+	}{
+		{
+			name:    "license added",
+			path:    "foo.go",
+			content: "package foo",
+			wantOutput: `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+		},
+		{
+			name:    "custom license added based on path",
+			path:    "bar/bar.go",
+			content: "package bar",
+			wantOutput: `// Copyright 2016 Custom Co.
+
+package bar`,
+		},
+		{
+			name:       "excluded content written unmodified",
+			path:       "excluded.go",
+			content:    "package foo",
+			wantOutput: "package foo",
+		},
+		{
+			name:       "non-Go content written unmodified",
+			path:       "foo.txt",
+			content:    "foo",
+			wantOutput: "foo",
+		},
+		{
+			name:   "license removed",
+			path:   "foo.go",
+			remove: true,
+			content: `// Copyright 2016 Palantir Technologies, Inc.
+package foo`,
+			wantOutput: "package foo",
+		},
+		{
+			name:   "verify succeeds with no output",
+			path:   "foo.go",
+			verify: true,
+			content: `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+		},
+		{
+			name:       "verify fails",
+			path:       "bar/bar.go",
+			verify:     true,
+			content:    "package bar",
+			wantOutput: "1 file does not have the correct license header:\n\tbar/bar.go (no license header)\n",
+			wantErr:    true,
+		},
+		{
+			name:   "verify fails for license treated as package documentation",
+			path:   "foo.go",
+			verify: true,
+			content: `// Copyright 2016 Palantir Technologies, Inc.
+package foo`,
+			wantOutput: "1 file has a license header that is treated as package documentation (add a blank line after it):\n\tfoo.go\n",
+			wantErr:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := golicense.RunLicenseStdin(tc.path, projectParam, tc.verify, tc.remove, strings.NewReader(tc.content), buf)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantOutput, buf.String())
+		})
+	}
+}
+
 func TestValidateCustomLicenseParams(t *testing.T) {
 	for _, tc := range []struct {
 		name          string
@@ -468,6 +1102,350 @@ func TestValidateCustomLicenseParams(t *testing.T) {
 			},
 			wantErr: "the same path is defined by multiple custom header entries:\n\tbar: foo, bar, collides",
 		},
+		{
+			name: "file types valid",
+			projectConfig: config.ProjectConfig{
+				FileTypes: config.ToFileTypeConfigs([]config.FileTypeConfig{
+					{
+						Name:         "shell",
+						Names:        []string{`.*\.sh`},
+						CommentStyle: "#",
+					},
+				}),
+			},
+		},
+		{
+			name: "file type with invalid comment style invalid",
+			projectConfig: config.ProjectConfig{
+				FileTypes: config.ToFileTypeConfigs([]config.FileTypeConfig{
+					{
+						Name:         "shell",
+						Names:        []string{`.*\.sh`},
+						CommentStyle: ";",
+					},
+				}),
+			},
+			wantErr: `invalid configuration for file type shell: invalid comment style ";": must be one of "//", "#", "--", "/* */" or "{{/* */}}"`,
+		},
+		{
+			name: "header styles valid",
+			projectConfig: config.ProjectConfig{
+				Header:      "Copyright 2016 Palantir Technologies, Inc.\n",
+				HeaderStyle: "block",
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:        "foo",
+						Header:      "Copyright 2016 Foo Co.\n",
+						HeaderStyle: "line",
+						Paths:       []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "invalid header style invalid",
+			projectConfig: config.ProjectConfig{
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:        "foo",
+						Header:      "Copyright 2016 Foo Co.\n",
+						HeaderStyle: "#",
+						Paths:       []string{"foo"},
+					},
+				}),
+			},
+			wantErr: `invalid configuration for custom header foo: invalid header style "#": must be "line" or "block"`,
+		},
+		{
+			name: "header that is not a Go comment invalid",
+			projectConfig: config.ProjectConfig{
+				Header: "// Copyright 2016 Palantir Technologies, Inc.\nAll rights reserved.\n",
+			},
+			wantErr: `header is not a valid Go comment: line is not a comment: "All rights reserved."`,
+		},
+		{
+			name: "custom header that is not a Go comment invalid",
+			projectConfig: config.ProjectConfig{
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:   "foo",
+						Header: "/* Copyright 2016 Foo Co.",
+						Paths:  []string{"foo"},
+					},
+				}),
+			},
+			wantErr: `invalid configuration for custom header foo: header is not a valid Go comment: comment not terminated: "/* Copyright 2016 Foo Co."`,
+		},
+		{
+			name: "SPDX headers valid",
+			projectConfig: config.ProjectConfig{
+				SPDX:            "Apache-2.0 OR MIT",
+				CopyrightHolder: "Palantir Technologies, Inc.",
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:        "foo",
+						SPDX:        "MPL-2.0",
+						HeaderStyle: "block",
+						Paths:       []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "notice headers valid",
+			projectConfig: config.ProjectConfig{
+				Notice:          "Apache-2.0",
+				CopyrightHolder: "Palantir Technologies, Inc.",
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:            "foo",
+						Notice:          "proprietary",
+						CopyrightHolder: "Foo Co.",
+						CopyrightYear:   "2016",
+						Paths:           []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "unknown notice invalid",
+			projectConfig: config.ProjectConfig{
+				Notice:          "GPL-3.0",
+				CopyrightHolder: "Palantir Technologies, Inc.",
+			},
+			wantErr: `unknown license notice "GPL-3.0": must be one of Apache-2.0, BSD-3-Clause, MIT, MPL-2.0, proprietary`,
+		},
+		{
+			name: "notice without copyright holder invalid",
+			projectConfig: config.ProjectConfig{
+				Notice: "MIT",
+			},
+			wantErr: "license notice MIT requires a copyright holder",
+		},
+		{
+			name: "invalid SPDX expression invalid",
+			projectConfig: config.ProjectConfig{
+				SPDX: "Apache-2.0 OR Foo-1.0",
+			},
+			wantErr: `invalid SPDX license expression "Apache-2.0 OR Foo-1.0": unknown license identifier "Foo-1.0"`,
+		},
+		{
+			name: "header and SPDX expression invalid",
+			projectConfig: config.ProjectConfig{
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:   "foo",
+						Header: "// Header",
+						SPDX:   "MIT",
+						Paths:  []string{"foo"},
+					},
+				}),
+			},
+			wantErr: "invalid configuration for custom header foo: only one of header, spdx and notice can be specified",
+		},
+		{
+			name: "copyright holder without SPDX expression invalid",
+			projectConfig: config.ProjectConfig{
+				Header:          "// Header",
+				CopyrightHolder: "Palantir Technologies, Inc.",
+			},
+			wantErr: "copyright-holder and copyright-year can only be specified with spdx or notice",
+		},
+		{
+			name: "accepted headers valid",
+			projectConfig: config.ProjectConfig{
+				Header:          "// Copyright {{YEAR}} New Co.",
+				AcceptedHeaders: []string{"// Copyright {{YEAR}} Old Co."},
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:            "foo",
+						SPDX:            "MIT",
+						CopyrightHolder: "New Co.",
+						HeaderStyle:     "block",
+						AcceptedHeaders: []string{"Copyright {{YEAR}} Old Co.\nSPDX-License-Identifier: MIT"},
+						Paths:           []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "invalid accepted header invalid",
+			projectConfig: config.ProjectConfig{
+				Header:          "// Copyright {{YEAR}} New Co.",
+				AcceptedHeaders: []string{"Copyright {{YEAR}} Old Co."},
+			},
+			wantErr: `header is not a valid Go comment: line is not a comment: "Copyright {{YEAR}} Old Co."`,
+		},
+		{
+			name: "accepted headers without header invalid",
+			projectConfig: config.ProjectConfig{
+				AcceptedHeaders: []string{"// Copyright {{YEAR}} Old Co."},
+			},
+			wantErr: "accepted-headers can only be specified with header, spdx or notice",
+		},
+		{
+			name: "header regex valid",
+			projectConfig: config.ProjectConfig{
+				Header:      "// Copyright {{YEAR}} Palantir Technologies, Inc.\n",
+				HeaderRegex: "// Copyright {{YEAR}} Palantir Technologies, Inc\\.( All rights reserved\\.)?\n",
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:            "foo",
+						SPDX:            "MIT",
+						CopyrightHolder: "Foo Co.",
+						HeaderRegex:     "// Copyright {{YEAR}} Foo (Co\\.|Company)\n// SPDX-License-Identifier: MIT",
+						Paths:           []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "header regex with header style valid",
+			projectConfig: config.ProjectConfig{
+				Header:      "Copyright {{YEAR}} Palantir Technologies, Inc.\n",
+				HeaderStyle: "block",
+				HeaderRegex: "Copyright {{YEAR}} Palantir Technologies, Inc\\.( All rights reserved\\.)?\n",
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:        "foo",
+						Header:      "Copyright {{YEAR}} Foo Co.",
+						HeaderStyle: "line",
+						HeaderRegex: "Copyright {{YEAR}} Foo (Co\\.|Company)",
+						Paths:       []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "header regex that does not match header invalid",
+			projectConfig: config.ProjectConfig{
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:        "foo",
+						Header:      "// Copyright 2016 Foo Co.",
+						HeaderRegex: "// Copyright {{YEAR}} Foo Company",
+						Paths:       []string{"foo"},
+					},
+				}),
+			},
+			wantErr: `invalid configuration for custom header foo: header regular expression "// Copyright {{YEAR}} Foo Company" does not match the header "// Copyright 2016 Foo Co."`,
+		},
+		{
+			name: "invalid header regex",
+			projectConfig: config.ProjectConfig{
+				Header:      "// Copyright {{YEAR}} Foo Co.",
+				HeaderRegex: "// Copyright {{YEAR}} Foo (Co\\.",
+			},
+			wantErr: "invalid header regular expression: error parsing regexp: missing closing ): `// Copyright {{YEAR}} Foo (Co\\.`",
+		},
+		{
+			name: "header regex without header invalid",
+			projectConfig: config.ProjectConfig{
+				HeaderRegex: "// Copyright {{YEAR}} Foo Co\\.",
+			},
+			wantErr: "header-regex can only be specified with header, spdx or notice",
+		},
+		{
+			name: "blank lines after header valid",
+			projectConfig: config.ProjectConfig{
+				Header:          "// Header\n",
+				BlankLinesAfter: 1,
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:            "foo",
+						Header:          "// Foo header",
+						BlankLinesAfter: 2,
+						Paths:           []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "negative blank lines after header invalid",
+			projectConfig: config.ProjectConfig{
+				Header:          "// Header",
+				BlankLinesAfter: -1,
+			},
+			wantErr: "blank-lines-after must not be negative: -1",
+		},
+		{
+			name: "header ending with more blank lines than blank lines after header invalid",
+			projectConfig: config.ProjectConfig{
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:            "foo",
+						Header:          "// Foo header\n\n",
+						BlankLinesAfter: 1,
+						Paths:           []string{"foo"},
+					},
+				}),
+			},
+			wantErr: "invalid configuration for custom header foo: header ends with 2 blank lines, which is more than blank-lines-after (1)",
+		},
+		{
+			name: "third-party policies valid",
+			projectConfig: config.ProjectConfig{
+				Header:     "// Header",
+				ThirdParty: "add-below",
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:       "foo",
+						Header:     "// Foo header",
+						ThirdParty: "skip",
+						Paths:      []string{"foo"},
+					},
+				}),
+			},
+		},
+		{
+			name: "unknown third-party policy invalid",
+			projectConfig: config.ProjectConfig{
+				CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+					{
+						Name:       "foo",
+						Header:     "// Foo header",
+						ThirdParty: "ignore",
+						Paths:      []string{"foo"},
+					},
+				}),
+			},
+			wantErr: `invalid configuration for custom header foo: invalid third-party policy "ignore": must be one of "add-above", "skip", "add-below" or "fail"`,
+		},
+		{
+			name: "file type replacing built-in file type valid",
+			projectConfig: config.ProjectConfig{
+				FileTypes: config.ToFileTypeConfigs([]config.FileTypeConfig{
+					{Name: "c", Names: []string{`.*\.c`}, CommentStyle: "/* */"},
+				}),
+			},
+		},
+		{
+			name: "file type without names invalid",
+			projectConfig: config.ProjectConfig{
+				FileTypes: config.ToFileTypeConfigs([]config.FileTypeConfig{
+					{Name: "c"},
+				}),
+			},
+			wantErr: "file type c must specify at least one name",
+		},
+		{
+			name: "non-unique file type names invalid",
+			projectConfig: config.ProjectConfig{
+				FileTypes: config.ToFileTypeConfigs([]config.FileTypeConfig{
+					{
+						Name:         "shell",
+						Names:        []string{`.*\.sh`},
+						CommentStyle: "#",
+					},
+					{
+						Name:         "shell",
+						Names:        []string{`.*\.bash`},
+						CommentStyle: "#",
+					},
+				}),
+			},
+			wantErr: "file type shell defined multiple times",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			_, err := tc.projectConfig.ToParam()
@@ -480,6 +1458,68 @@ func TestValidateCustomLicenseParams(t *testing.T) {
 	}
 }
 
+func TestBuiltinFileTypesConfig(t *testing.T) {
+	projectConfig := config.ProjectConfig{
+		Header: "// Copyright 2016 Palantir Technologies, Inc.",
+		CustomHeaders: config.ToCustomHeaderConfigs([]config.CustomHeaderConfig{
+			{
+				Name:   "proto",
+				Header: "// Copyright 2016 Proto Co.",
+				Paths:  []string{"proto"},
+			},
+		}),
+		Exclude: matcher.NamesPathsCfg{
+			Names: []string{`.*\.tmpl`},
+		},
+		FileTypes: config.ToFileTypeConfigs([]config.FileTypeConfig{
+			{Name: "c", Names: []string{`.*\.c`}, CommentStyle: "/* */"},
+		}),
+	}
+	projectParam, err := projectConfig.ToParam()
+	require.NoError(t, err)
+
+	// the built-in file types are processed without being configured, custom headers and exclusions apply to them, and a
+	// configured file type replaces the built-in file type with the same name
+	for _, tc := range []struct {
+		path       string
+		wantStatus golicense.Status
+		want       string
+	}{
+		{"foo_amd64.s", golicense.StatusModified, "// Copyright 2016 Palantir Technologies, Inc.\n\nfoo\n"},
+		{"proto/foo.proto", golicense.StatusModified, "// Copyright 2016 Proto Co.\n\nfoo\n"},
+		{"foo.tmpl", golicense.StatusSkipped, "foo\n"},
+		{"foo.c", golicense.StatusModified, "/*\nCopyright 2016 Palantir Technologies, Inc.\n*/\n\nfoo\n"},
+		{"foo.h", golicense.StatusSkipped, "foo\n"},
+	} {
+		got, status := golicense.LicenseContent(tc.path, []byte("foo\n"), projectParam)
+		assert.Equal(t, tc.wantStatus, status, tc.path)
+		assert.Equal(t, tc.want, string(got), tc.path)
+	}
+}
+
+func TestHeaderRegexHeaderStyle(t *testing.T) {
+	projectConfig := config.ProjectConfig{
+		Header:      "Copyright {{YEAR}} Palantir Technologies, Inc.",
+		HeaderStyle: "block",
+		HeaderRegex: `Copyright {{YEAR}} Palantir Technologies, Inc\.( All rights reserved\.)?`,
+	}
+	projectParam, err := projectConfig.ToParam()
+	require.NoError(t, err)
+
+	// the regular expression is rendered in the header style, and the other rendering of the header is also matched
+	for _, content := range []string{
+		"/*\nCopyright 2016 Palantir Technologies, Inc. All rights reserved.\n*/\n\npackage foo\n",
+		"// Copyright 2016 Palantir Technologies, Inc. All rights reserved.\n\npackage foo\n",
+	} {
+		got, status := golicense.LicenseContent("foo.go", []byte(content), projectParam)
+		assert.Equal(t, golicense.StatusUnchanged, status, content)
+		assert.Equal(t, content, string(got))
+		got, status = golicense.UnlicenseContent("foo.go", []byte(content), projectParam)
+		assert.Equal(t, golicense.StatusModified, status, content)
+		assert.Equal(t, "package foo\n", string(got))
+	}
+}
+
 func chdir(t *testing.T, dest string) func() {
 	orig, err := os.Getwd()
 	require.NoError(t, err)
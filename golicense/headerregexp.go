@@ -0,0 +1,100 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NewRegexpLicenser returns a Licenser that adds the provided license header (see NewLicenser) but that matches and
+// removes any header that matches the provided regular expression, which can express variations that "{{YEAR}}" cannot
+// (for example, an optional "All rights reserved." or alternative company names). The regular expression is matched
+// at the start of the content and is treated in the same manner as a header: each of its lines must match an entire
+// line of the content (tolerating trailing whitespace and CRLF line endings), and "{{YEAR}}" matches any 4-digit
+// number. Each line of the regular expression must start with "//" so that it can be rendered in other comment styles
+// in the same manner as a header: the "//" is replaced with the line comment prefix of the style, or the lines are
+// enclosed in the delimiters of a block comment style. The regular expression is rendered in the style of the license
+// (so it is enclosed in "/* */" if the license is a "/* */" comment), and the other rendering of the license ("/* */"
+// for a "//" license and vice versa) is matched by the regular expression rendered in that style. Headers in the
+// provided accepted headers are also matched and removed (see NewLicenserWithAccepted).
+//
+// Returns an error if the regular expression is invalid or if it does not match the license, since content to which
+// the license is added must match it.
+func NewRegexpLicenser(license, headerRegexp string, accepted ...string) (Licenser, error) {
+	if _, err := regexp.Compile(headerRegexp); err != nil {
+		return nil, errors.Wrapf(err, "invalid header regular expression")
+	}
+	body, _ := splitTrailingNewlines(headerRegexp)
+	for _, line := range strings.Split(body, "\n") {
+		if !strings.HasPrefix(line, "//") {
+			return nil, errors.Errorf(`header regular expression %q must start every line with "//"`, headerRegexp)
+		}
+	}
+	if _, err := regexp.Compile(headerRegexpPattern(headerRegexp)); err != nil {
+		return nil, errors.Wrapf(err, "invalid header regular expression")
+	}
+	l := NewLicenserWithAccepted(license, accepted...).(*licenserImpl)
+	style := SlashStyle
+	if _, ok := SlashStyle.Strip(license); !ok {
+		if _, ok := BlockStyle.Strip(license); ok {
+			style = BlockStyle
+		}
+	}
+	l.setHeaderRegexp(headerRegexp, style)
+	if !l.Matches(l.Add("package foo\n")) {
+		return nil, errors.Errorf("header regular expression %q does not match the header %q", headerRegexp, l.newLicenseHeader)
+	}
+	return l, nil
+}
+
+// setHeaderRegexp makes the Licenser match the provided header regular expression, whose lines start with "//",
+// rendered in the provided comment style, which is the style of its license. Its alternate renderings match the
+// regular expression rendered in their style: "/* */" for a "//" license and "//" for a "/* */" license.
+func (l *licenserImpl) setHeaderRegexp(headerRegexp string, style CommentStyle) {
+	// the regular expression is valid in the "//" style, so it remains valid once the other style is quoted
+	l.headerRegexp = headerRegexp
+	l.matchRegexp = regexp.MustCompile(headerRegexpPattern(restyleHeaderRegexp(headerRegexp, style)))
+	l.spdxExprs = nil
+	alternateStyle := BlockStyle
+	if style == BlockStyle {
+		alternateStyle = SlashStyle
+	}
+	for _, alternate := range l.alternates {
+		alternate.setHeaderRegexp(headerRegexp, alternateStyle)
+	}
+}
+
+// headerRegexpPattern returns the pattern of the regular expression that matches the start of content as described by
+// NewRegexpLicenser for the provided header regular expression.
+func headerRegexpPattern(headerRegexp string) string {
+	lines := strings.Split(headerRegexp, "\n")
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(strings.TrimRight(line, " \t\r"), "{{YEAR}}", `(?P<year>\d\d\d\d)`)
+	}
+	const lineEnd = `[ \t]*\r?\n`
+	return `^(?:` + strings.Join(lines, lineEnd) + `)` + lineEnd
+}
+
+// restyleHeaderRegexp returns the provided header regular expression, whose lines start with "//", rendered in the
+// provided comment style in the same manner as CommentStyle.Render renders a header.
+func restyleHeaderRegexp(headerRegexp string, style CommentStyle) string {
+	body, trailing := splitTrailingNewlines(headerRegexp)
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		line = strings.TrimPrefix(line, "//")
+		if style.LinePrefix != "" {
+			lines[i] = regexp.QuoteMeta(style.LinePrefix) + line
+		} else {
+			lines[i] = strings.TrimPrefix(line, " ")
+		}
+	}
+	if style.LinePrefix == "" {
+		lines = append(append([]string{regexp.QuoteMeta(style.BlockStart)}, lines...), regexp.QuoteMeta(style.BlockEnd))
+	}
+	return strings.Join(lines, "\n") + trailing
+}
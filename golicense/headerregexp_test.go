@@ -0,0 +1,167 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/palantir/pkg/matcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegexpLicenser(t *testing.T) {
+	licenser, err := golicense.NewRegexpLicenser(
+		"// Copyright {{YEAR}} Palantir Technologies, Inc.\n// Licensed under the Apache License, Version 2.0.",
+		`// Copyright {{YEAR}} (Palantir Technologies|Palantir), Inc\.( All rights reserved\.)?`+"\n"+`// Licensed under the Apache License, Version 2\.0\.`,
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		content     string
+		wantMatch   bool
+		wantRemoved string
+	}{
+		{
+			name:        "header matches",
+			content:     "// Copyright 2016 Palantir Technologies, Inc.\n// Licensed under the Apache License, Version 2.0.\n\npackage foo\n",
+			wantMatch:   true,
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:        "variant matches",
+			content:     "// Copyright 2016 Palantir, Inc. All rights reserved.\n// Licensed under the Apache License, Version 2.0.\n\npackage foo\n",
+			wantMatch:   true,
+			wantRemoved: "package foo\n",
+		},
+		{
+			name:        "variant with CRLF line endings matches",
+			content:     "// Copyright 2016 Palantir, Inc.  \r\n// Licensed under the Apache License, Version 2.0.\r\n\r\npackage foo\r\n",
+			wantMatch:   true,
+			wantRemoved: "package foo\r\n",
+		},
+		{
+			name:        "partial line does not match",
+			content:     "// Copyright 2016 Palantir, Inc. All rights reserved. Foo.\n// Licensed under the Apache License, Version 2.0.\n\npackage foo\n",
+			wantRemoved: "// Copyright 2016 Palantir, Inc. All rights reserved. Foo.\n// Licensed under the Apache License, Version 2.0.\n\npackage foo\n",
+		},
+		{
+			name:        "variant in other rendering matches",
+			content:     "/*\nCopyright 2016 Palantir, Inc. All rights reserved.\nLicensed under the Apache License, Version 2.0.\n*/\n\npackage foo\n",
+			wantMatch:   true,
+			wantRemoved: "package foo\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantMatch, licenser.Matches(tc.content))
+			if tc.wantMatch {
+				assert.Equal(t, tc.wantRemoved, licenser.Remove(tc.content))
+			}
+		})
+	}
+
+	// the header is added as specified
+	assert.Equal(t, fmt.Sprintf("// Copyright %d Palantir Technologies, Inc.\n// Licensed under the Apache License, Version 2.0.\n\npackage foo\n", time.Now().Year()), licenser.Add("package foo\n"))
+
+	_, err = golicense.NewRegexpLicenser("// Copyright {{YEAR}} Palantir Technologies, Inc.", `// Copyright (Palantir`)
+	assert.EqualError(t, err, "invalid header regular expression: error parsing regexp: missing closing ): `// Copyright (Palantir`")
+
+	_, err = golicense.NewRegexpLicenser("// Copyright {{YEAR}} Palantir Technologies, Inc.", `(//|#) Copyright {{YEAR}} Palantir Technologies, Inc\.`)
+	assert.EqualError(t, err, `header regular expression "(//|#) Copyright {{YEAR}} Palantir Technologies, Inc\\." must start every line with "//"`)
+
+	_, err = golicense.NewRegexpLicenser("// Copyright {{YEAR}} Palantir Technologies, Inc.", `// Copyright {{YEAR}} Palantir, Inc\.`)
+	assert.EqualError(t, err, `header regular expression "// Copyright {{YEAR}} Palantir, Inc\\." does not match the header "`+fmt.Sprintf("// Copyright %d Palantir Technologies, Inc.", time.Now().Year())+`"`)
+}
+
+func TestNewRegexpLicenserBlockStyle(t *testing.T) {
+	// the regular expression is rendered in the style of the license
+	licenser, err := golicense.NewRegexpLicenser("/*\nCopyright {{YEAR}} Foo Inc.\n*/", `// Copyright {{YEAR}} Foo( Inc\.)?`)
+	require.NoError(t, err)
+
+	for _, content := range []string{
+		"/*\nCopyright 2019 Foo\n*/\n\npackage a\n",
+		"// Copyright 2019 Foo\n\npackage a\n",
+	} {
+		assert.True(t, licenser.Matches(content), content)
+		assert.Equal(t, "package a\n", licenser.Remove(content), content)
+	}
+	assert.False(t, licenser.Matches("/*\nCopyright 2019 Bar\n*/\n\npackage a\n"))
+	assert.Equal(t, fmt.Sprintf("/*\nCopyright %d Foo Inc.\n*/\n\npackage a\n", time.Now().Year()), licenser.Add("package a\n"))
+}
+
+func TestNewRegexpLicenserFileTypes(t *testing.T) {
+	licenser, err := golicense.NewRegexpLicenser("// Copyright {{YEAR}} Foo Inc.\n", `// Copyright {{YEAR}} Foo( Inc\.)?`+"\n")
+	require.NoError(t, err)
+	tmplType, ok := golicense.BuiltinFileType("tmpl")
+	require.True(t, ok)
+	projectParam := golicense.ProjectParam{
+		Licenser: licenser,
+		FileTypes: []golicense.FileTypeParam{
+			{Name: "sh", Matcher: matcher.Name(`.*\.sh`), CommentStyle: golicense.HashStyle},
+			tmplType,
+		},
+	}
+	year := time.Now().Year()
+
+	for _, tc := range []struct {
+		name       string
+		path       string
+		content    string
+		wantStatus golicense.Status
+		want       string
+	}{
+		{
+			name:       "variant in Go file",
+			path:       "a.go",
+			content:    "// Copyright 2019 Foo\n\npackage a\n",
+			wantStatus: golicense.StatusUnchanged,
+			want:       "// Copyright 2019 Foo\n\npackage a\n",
+		},
+		{
+			name:       "variant in line comment style",
+			path:       "a.sh",
+			content:    "# Copyright 2019 Foo\n\necho a\n",
+			wantStatus: golicense.StatusUnchanged,
+			want:       "# Copyright 2019 Foo\n\necho a\n",
+		},
+		{
+			name:       "variant in block comment style",
+			path:       "a.tmpl",
+			content:    "{{/*\nCopyright 2019 Foo\n*/ -}}\n\na\n",
+			wantStatus: golicense.StatusUnchanged,
+			want:       "{{/*\nCopyright 2019 Foo\n*/ -}}\n\na\n",
+		},
+		{
+			name:       "header added in line comment style",
+			path:       "a.sh",
+			content:    "echo a\n",
+			wantStatus: golicense.StatusModified,
+			want:       fmt.Sprintf("# Copyright %d Foo Inc.\n\necho a\n", year),
+		},
+		{
+			name:       "Go rendering not matched in line comment style",
+			path:       "a.sh",
+			content:    "// Copyright 2019 Foo\n\necho a\n",
+			wantStatus: golicense.StatusModified,
+			want:       fmt.Sprintf("# Copyright %d Foo Inc.\n\n// Copyright 2019 Foo\n\necho a\n", year),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, status := golicense.LicenseContent(tc.path, []byte(tc.content), projectParam)
+			assert.Equal(t, tc.wantStatus, status)
+			assert.Equal(t, tc.want, string(got))
+
+			if tc.wantStatus == golicense.StatusUnchanged {
+				removed, status := golicense.UnlicenseContent(tc.path, []byte(tc.content), projectParam)
+				assert.Equal(t, golicense.StatusModified, status)
+				assert.NotContains(t, string(removed), "Copyright")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"embed"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	//go:embed notices/*.txt
+	noticesFS embed.FS
+
+	// maps the lowercase form of the identifier of each notice to the path of its template in noticesFS
+	noticePaths = func() map[string]string {
+		entries, err := noticesFS.ReadDir("notices")
+		if err != nil {
+			panic(err)
+		}
+		paths := make(map[string]string)
+		for _, entry := range entries {
+			paths[strings.ToLower(strings.TrimSuffix(entry.Name(), ".txt"))] = path.Join("notices", entry.Name())
+		}
+		return paths
+	}()
+)
+
+// NoticeIDs returns the identifiers of the standard license notices in the catalog in sorted order. The identifiers
+// are "Apache-2.0", "BSD-3-Clause", "MIT", "MPL-2.0" (the SPDX identifiers of the licenses) and "proprietary" (a
+// notice for code that is not licensed for use by others).
+func NoticeIDs() []string {
+	ids := make([]string, 0, len(noticePaths))
+	for _, p := range noticePaths {
+		ids = append(ids, strings.TrimSuffix(path.Base(p), ".txt"))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Notice returns the plain text of the standard license notice with the provided identifier (which is
+// case-insensitive) for the provided copyright holder. The notice starts with a copyright line for the holder and the
+// provided year, which may be a single year or a range such as "2016-2024". If year is empty, the notice uses
+// "{{YEAR}}", which NewLicenser treats as the current year when the license is added and as any year when it is
+// matched. The returned text is not a comment: use CommentStyle.Render to render it in a comment style.
+func Notice(id, holder, year string) (string, error) {
+	p, ok := noticePaths[strings.ToLower(id)]
+	if !ok {
+		return "", errors.Errorf("unknown license notice %q: must be one of %s", id, strings.Join(NoticeIDs(), ", "))
+	}
+	if strings.TrimSpace(holder) == "" {
+		return "", errors.Errorf("license notice %s requires a copyright holder", id)
+	}
+	template, err := noticesFS.ReadFile(p)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read license notice %s", id)
+	}
+	text := strings.TrimRight(string(template), "\n")
+	text = strings.ReplaceAll(text, "{{HOLDER}}", strings.TrimSpace(holder))
+	if year != "" {
+		text = strings.ReplaceAll(text, "{{YEAR}}", year)
+	}
+	return text, nil
+}
+
+// NewNoticeLicenser returns a Licenser for the standard license notice with the provided identifier, holder and year
+// (see Notice) rendered as "//" comments.
+func NewNoticeLicenser(id, holder, year string) (Licenser, error) {
+	text, err := Notice(id, holder, year)
+	if err != nil {
+		return nil, err
+	}
+	return NewLicenser(SlashStyle.Render(text)), nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotice(t *testing.T) {
+	assert.Equal(t, []string{"Apache-2.0", "BSD-3-Clause", "MIT", "MPL-2.0", "proprietary"}, golicense.NoticeIDs())
+
+	for _, id := range golicense.NoticeIDs() {
+		t.Run(id, func(t *testing.T) {
+			text, err := golicense.Notice(id, "Palantir Technologies, Inc.", "")
+			require.NoError(t, err)
+			assert.Contains(t, text, "{{YEAR}} Palantir Technologies, Inc.")
+			assert.NotContains(t, text, "{{HOLDER}}")
+		})
+	}
+
+	text, err := golicense.Notice("bsd-3-clause", "The Go Authors", "2009-2016")
+	require.NoError(t, err)
+	assert.Equal(t, `Copyright 2009-2016 The Go Authors. All rights reserved.
+Use of this source code is governed by a BSD-style
+license that can be found in the LICENSE file.`, text)
+
+	_, err = golicense.Notice("GPL-3.0", "Palantir Technologies, Inc.", "")
+	assert.EqualError(t, err, `unknown license notice "GPL-3.0": must be one of Apache-2.0, BSD-3-Clause, MIT, MPL-2.0, proprietary`)
+
+	_, err = golicense.Notice("MIT", " ", "")
+	assert.EqualError(t, err, "license notice MIT requires a copyright holder")
+}
+
+func TestNewNoticeLicenser(t *testing.T) {
+	licenser, err := golicense.NewNoticeLicenser("MIT", "Palantir Technologies, Inc.", "")
+	require.NoError(t, err)
+
+	want := fmt.Sprintf(`// Copyright (c) %d Palantir Technologies, Inc.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package foo
+`, time.Now().Year())
+	assert.Equal(t, want, licenser.Add("package foo\n"))
+	assert.True(t, licenser.Matches(`// Copyright (c) 2016 Palantir Technologies, Inc.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package foo
+`))
+}
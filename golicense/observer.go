@@ -0,0 +1,85 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	// EventFileSelected indicates that a file was selected for processing. The HeaderName of the event is the name of
+	// the custom header that applies to the file, or empty if the default header applies.
+	EventFileSelected EventType = iota
+	// EventFileExcluded indicates that a file was not selected for processing because it matches no configured file
+	// type ("*.go" files and the file types of ProjectParam.FileTypes) or it is excluded by the configuration.
+	EventFileExcluded
+	// EventFileMatched indicates that the content of a selected file matched the license header that applies to it.
+	EventFileMatched
+	// EventFileModified indicates that a selected file was modified. When verifying, this indicates that the file would
+	// have been modified (that is, it does not have the correct license header).
+	EventFileModified
+	// EventError indicates that an error occurred while processing a file. The Err of the event is the error.
+	EventError
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventFileSelected:
+		return "selected"
+	case EventFileExcluded:
+		return "excluded"
+	case EventFileMatched:
+		return "matched"
+	case EventFileModified:
+		return "modified"
+	case EventError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a step in the processing of a single file.
+type Event struct {
+	Type EventType
+	// Path is the path of the file.
+	Path string
+	// HeaderName is the name of the custom header that applies to the file, or empty if the default header applies.
+	HeaderName string
+	// Err is the error that occurred for events of type EventError.
+	Err error
+}
+
+// Observer is called with the events that occur while files are processed. Events for a file are delivered in the
+// order in which they occur on the goroutine that is processing the files, so an Observer does not need to be safe
+// for concurrent use but should return quickly.
+type Observer func(event Event)
+
+func (o Observer) notify(event Event) {
+	if o != nil {
+		o(event)
+	}
+}
+
+// withHeaderName returns an Observer that sets the HeaderName of all of the events it receives to the provided value
+// before delegating to o.
+func (o Observer) withHeaderName(headerName string) Observer {
+	if o == nil {
+		return nil
+	}
+	return func(event Event) {
+		event.HeaderName = headerName
+		o(event)
+	}
+}
+
+// notifyResult notifies the observer of the result of processing the file at the provided path.
+func notifyResult(observer Observer, path string, matched, changed bool) {
+	if matched {
+		observer.notify(Event{Type: EventFileMatched, Path: path})
+	}
+	if changed {
+		observer.notify(Event{Type: EventFileModified, Path: path})
+	}
+}
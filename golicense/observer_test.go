@@ -0,0 +1,69 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/palantir/pkg/matcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldWd := chdir(t, tmpDir)
+	defer oldWd()
+
+	writeFiles(t, tmpDir, map[string]string{
+		"foo.go": `package foo`,
+		"licensed.go": `// Copyright 2016 Palantir Technologies, Inc.
+
+package foo`,
+		"excluded.go": `package foo`,
+		"foo.txt":     `foo`,
+		"bar/bar.go":  `package bar`,
+	})
+
+	var events []golicense.Event
+	projectParam := golicense.ProjectParam{
+		Licenser: golicense.NewLicenser(`// Copyright 2016 Palantir Technologies, Inc.`),
+		CustomHeaders: []golicense.CustomHeaderParam{
+			{
+				Name:         "Custom Co.",
+				Licenser:     golicense.NewLicenser("// Copyright 2016 Custom Co."),
+				IncludePaths: []string{"bar"},
+			},
+		},
+		Exclude: matcher.Name("excluded.go"),
+		Observer: func(event golicense.Event) {
+			events = append(events, event)
+		},
+	}
+
+	modified, err := golicense.LicenseFiles([]string{"foo.go", "licensed.go", "excluded.go", "foo.txt", "bar/bar.go"}, projectParam)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar/bar.go", "foo.go"}, modified)
+	assert.Equal(t, []golicense.Event{
+		{Type: golicense.EventFileExcluded, Path: "excluded.go"},
+		{Type: golicense.EventFileExcluded, Path: "foo.txt"},
+		{Type: golicense.EventFileSelected, Path: "foo.go"},
+		{Type: golicense.EventFileSelected, Path: "licensed.go"},
+		{Type: golicense.EventFileSelected, Path: "bar/bar.go", HeaderName: "Custom Co."},
+		{Type: golicense.EventFileModified, Path: "bar/bar.go", HeaderName: "Custom Co."},
+		{Type: golicense.EventFileModified, Path: "foo.go"},
+		{Type: golicense.EventFileMatched, Path: "licensed.go"},
+	}, events)
+
+	events = nil
+	_, err = golicense.LicenseFiles([]string{"missing.go"}, projectParam)
+	require.Error(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, golicense.EventFileSelected, events[0].Type)
+	assert.Equal(t, golicense.EventError, events[1].Type)
+	assert.Equal(t, "missing.go", events[1].Path)
+	assert.Error(t, events[1].Err)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"go/parser"
+	"go/token"
+)
+
+// headerLocator is implemented by Licensers that can determine the location of their license in content.
+type headerLocator interface {
+	// headerBounds returns the start and end offsets of the license in the provided content (the end includes the
+	// final newline of the license). Returns false if the content does not have the license.
+	headerBounds(content string) (int, int, bool)
+}
+
+// headerIsPackageDoc returns true if the license of the provided Licenser in the provided Go file content is part of
+// the doc comment of the package clause, which occurs if there is no blank line between the license and the package
+// clause (or the package doc comment). Returns false if the Licenser cannot locate its license or the content cannot
+// be parsed.
+func headerIsPackageDoc(path, content string, licenser Licenser) bool {
+	locator, ok := licenser.(headerLocator)
+	if !ok {
+		return false
+	}
+	_, headerEnd, ok := locator.headerBounds(content)
+	if !ok {
+		return false
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil || file.Doc == nil {
+		return false
+	}
+	return fset.Position(file.Doc.Pos()).Offset < headerEnd
+}
+
+// separatePackageDoc returns the provided content with a blank line inserted after the license of the provided Licenser
+// if the content is a Go file in which the license is part of the package documentation (see headerIsPackageDoc).
+// Other content is returned unmodified.
+func separatePackageDoc(path, content string, licenser Licenser) string {
+	if !goFileType.Matcher.Match(path) || !headerIsPackageDoc(path, content, licenser) {
+		return content
+	}
+	_, headerEnd, _ := licenser.(headerLocator).headerBounds(content)
+	lineEnd := "\n"
+	if usesCRLF(content) {
+		lineEnd = "\r\n"
+	}
+	return content[:headerEnd] + lineEnd + content[headerEnd:]
+}
@@ -19,6 +19,44 @@ type ProjectParam struct {
 	// Exclude matches the files and directories that should be excluded from consideration for verifying or applying
 	// licenses.
 	Exclude matcher.Matcher
+
+	// ThirdPartyPolicy specifies how files to which the default Licenser applies are handled if they start with a
+	// third-party header (the copyright of another holder).
+	ThirdPartyPolicy ThirdPartyPolicy
+
+	// BlankLinesAfter is the number of blank lines between the default header and the rest of the content of a file.
+	// If it is positive, adding the header writes exactly this number of blank lines after it, removing the header
+	// removes all of the blank lines after it, and files that have the header followed by a different number of blank
+	// lines do not have the correct header. If 0, adding the header ensures that it is followed by at least one blank
+	// line, and removing the header removes the blank line that was added.
+	BlankLinesAfter int
+
+	// FileTypes specifies the types of files other than "*.go" files that should be processed. The license headers
+	// are specified as "//" comments and are rendered in the comment style of each file type. If a file matches
+	// multiple file types, the first matching type is used. "*.go" files are always processed using the "//" style.
+	FileTypes []FileTypeParam
+
+	// ConvertCommentStyle specifies that headers should be converted to the comment style in which they are specified.
+	// By default, a header specified as a "//" comment also matches the same text rendered as a "/* */" comment (and
+	// vice versa). If this is true, only the specified rendering matches: adding licenses replaces the other rendering
+	// with the specified one, and verification reports files that use the other rendering.
+	ConvertCommentStyle bool
+
+	// Strict specifies that only the canonical header of each Licenser matches. By default, the headers that a Licenser
+	// accepts in place of its own header (see NewLicenserWithAccepted) also match. If this is true, adding licenses
+	// replaces an accepted header with the canonical one, and verification reports files that have an accepted header.
+	Strict bool
+
+	// ParseCheck specifies that the new content of every Go file that is modified should be checked before it is
+	// written. The check verifies that the file still parses, declares the same package and has the same build
+	// constraints and "//go:" directives in the same positions. If the check fails, the file is not written, the
+	// remaining files are still processed, and an error that describes all of the files that failed the check is
+	// returned along with the files that were modified.
+	ParseCheck bool
+
+	// Observer, if non-nil, is notified of the events that occur while files are processed. It can be used to report
+	// progress or to collect the results for individual files.
+	Observer Observer
 }
 
 type CustomHeaderParam struct {
@@ -28,6 +66,14 @@ type CustomHeaderParam struct {
 	// Licenser for this parameter.
 	Licenser Licenser
 
+	// ThirdPartyPolicy specifies how files to which this parameter applies are handled if they start with a
+	// third-party header (the copyright of another holder).
+	ThirdPartyPolicy ThirdPartyPolicy
+
+	// BlankLinesAfter is the number of blank lines between the header of this parameter and the rest of the content of
+	// a file (see ProjectParam.BlankLinesAfter).
+	BlankLinesAfter int
+
 	// IncludePaths specifies the paths for which this custom license is applicable. If multiple custom parameters
 	// match a file or directory, the parameter with the longest path match is used. If multiple custom parameters
 	// match a file or directory exactly (match length is equal), it is treated as an error.
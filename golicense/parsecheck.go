@@ -0,0 +1,194 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// parseCheckFailures records the files whose new content failed the parse check. Such files are not written, and the
+// remaining files are still processed so that the failures can be reported together.
+type parseCheckFailures struct {
+	errs []string
+}
+
+// newParseCheckFailures returns an empty parseCheckFailures if check is true and nil otherwise.
+func newParseCheckFailures(check bool) *parseCheckFailures {
+	if !check {
+		return nil
+	}
+	return &parseCheckFailures{}
+}
+
+// err returns an error that describes all of the recorded failures, or nil if there are none.
+func (f *parseCheckFailures) err() error {
+	if f == nil || len(f.errs) == 0 {
+		return nil
+	}
+	description := "files failed the parse check and were not modified"
+	if len(f.errs) == 1 {
+		description = "file failed the parse check and was not modified"
+	}
+	return errors.Errorf("%d %s:\n\t%s", len(f.errs), description, strings.Join(f.errs, "\n\t"))
+}
+
+// writeModifiedFile writes the new content of the file at path and returns true if it was written. If failures is
+// non-nil, the new content is first checked using checkModifiedFile: if the check fails, the file is not written, the
+// failure is recorded in failures and sent to the observer, and false is returned.
+func writeModifiedFile(path, content, newContent string, perm os.FileMode, failures *parseCheckFailures, observer Observer) (bool, error) {
+	if failures != nil {
+		if err := checkModifiedFile(path, content, newContent); err != nil {
+			failures.errs = append(failures.errs, fmt.Sprintf("%s: %v", path, err))
+			observer.notify(Event{Type: EventError, Path: path, Err: errors.Wrapf(err, "parse check failed")})
+			return false, nil
+		}
+	}
+	if err := writeFileAtomic(path, []byte(newContent), perm); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkModifiedFile returns an error if the file at path is a Go file and its new content fails checkGoFile.
+func checkModifiedFile(path, content, newContent string) error {
+	if !goFileType.Matcher.Match(path) {
+		return nil
+	}
+	return checkGoFile(path, content, newContent)
+}
+
+// checkGoFile returns an error if the modified content of the Go file at path is no longer understood by the Go
+// toolchain in the same manner as its original content. The modified content must parse, declare the same package,
+// have the same build constraints and have the same "//go:" directives in the same positions relative to the package
+// clause. Returns nil if the original content does not parse, since the change cannot be checked in that case.
+func checkGoFile(path, content, newContent string) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, content, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	newFile, err := parser.ParseFile(fset, path, newContent, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return errors.Wrapf(err, "file no longer parses")
+	}
+	if newFile.Name.Name != file.Name.Name {
+		return errors.Errorf("package clause changed from %q to %q", file.Name.Name, newFile.Name.Name)
+	}
+
+	if constraints, newConstraints := buildConstraints(content), buildConstraints(newContent); !reflect.DeepEqual(constraints, newConstraints) {
+		return errors.Errorf("build constraints changed from %v to %v", constraints, newConstraints)
+	}
+
+	if directives, newDirectives := goDirectives(fset, file), goDirectives(fset, newFile); !reflect.DeepEqual(directives, newDirectives) {
+		return errors.Errorf(`"//go:" directives changed from %v to %v`, directives, newDirectives)
+	}
+	return nil
+}
+
+// buildConstraints returns the build constraint lines of the provided Go file content that are in effect. It follows
+// the rules of go/build: build constraints must appear in the leading run of blank lines and comments of the file, a
+// "//go:build" line takes precedence over "// +build" lines, and "// +build" lines must be followed by a blank line.
+func buildConstraints(content string) []string {
+	end := 0
+	goBuild := ""
+	ended := false       // found a line that is neither blank nor a "//" comment
+	inSlashStar := false // in a "/* */" comment
+	p := content
+Lines:
+	for len(p) > 0 {
+		line := p
+		if i := strings.IndexByte(line, '\n'); i >= 0 {
+			line, p = line[:i], p[i+1:]
+		} else {
+			p = ""
+		}
+		line = strings.TrimSpace(line)
+		if line == "" && !ended {
+			// "// +build" lines are only in effect before the last blank line of the header
+			end = len(content) - len(p)
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			ended = true
+		}
+		if !inSlashStar && goBuild == "" && isGoBuildLine(line) {
+			goBuild = line
+		}
+		for line != "" {
+			if inSlashStar {
+				i := strings.Index(line, "*/")
+				if i < 0 {
+					continue Lines
+				}
+				inSlashStar = false
+				line = strings.TrimSpace(line[i+len("*/"):])
+				continue
+			}
+			if strings.HasPrefix(line, "//") {
+				continue Lines
+			}
+			if strings.HasPrefix(line, "/*") {
+				inSlashStar = true
+				line = strings.TrimSpace(line[len("/*"):])
+				continue
+			}
+			// found content that is not a comment
+			break Lines
+		}
+	}
+
+	if goBuild != "" {
+		return []string{goBuild}
+	}
+	var constraints []string
+	for _, line := range strings.Split(content[:end], "\n") {
+		line = strings.TrimSpace(line)
+		if text, ok := strings.CutPrefix(line, "//"); ok {
+			if fields := strings.Fields(text); len(fields) > 0 && fields[0] == "+build" {
+				constraints = append(constraints, line)
+			}
+		}
+	}
+	return constraints
+}
+
+func isGoBuildLine(line string) bool {
+	return line == "//go:build" || strings.HasPrefix(line, "//go:build ") || strings.HasPrefix(line, "//go:build\t")
+}
+
+// goDirectives returns a description of each of the "//go:" directives in the provided file other than "//go:build"
+// (which is checked separately), including whether the directive is recognized by the toolchain (it must start at the
+// beginning of a line) and whether it precedes the package clause.
+func goDirectives(fset *token.FileSet, file *ast.File) []string {
+	var directives []string
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if !strings.HasPrefix(c.Text, "//go:") || isGoBuildLine(c.Text) {
+				continue
+			}
+			var attrs []string
+			if fset.Position(c.Pos()).Column != 1 {
+				attrs = append(attrs, "not at start of line")
+			}
+			if c.Pos() < file.Package {
+				attrs = append(attrs, "before package clause")
+			}
+			directive := c.Text
+			if len(attrs) > 0 {
+				directive = fmt.Sprintf("%s (%s)", directive, strings.Join(attrs, ", "))
+			}
+			directives = append(directives, directive)
+		}
+	}
+	return directives
+}
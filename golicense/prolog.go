@@ -0,0 +1,53 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"regexp"
+	"strings"
+)
+
+// utf8BOM is the UTF-8 encoding of the byte order mark.
+const utf8BOM = "\ufeff"
+
+// codingRegexp matches an encoding declaration as specified by PEP 263 (for example, "# -*- coding: utf-8 -*-").
+var codingRegexp = regexp.MustCompile(`^[ \t\f]*#.*?coding[:=][ \t]*[-\w.]+`)
+
+// splitProlog splits the provided content into its prolog and the remaining content. The prolog consists of the lines
+// at the start of a file that must remain at the start of the file and thus must precede the license header:
+//
+//   - an interpreter directive ("#!") on the first line
+//   - an XML declaration ("<?xml ... ?>") on the first line
+//   - an encoding declaration ("# -*- coding: ... -*-") on the first or second line
+//
+// If the content begins with a UTF-8 byte order mark, the prolog also includes it (and the lines above are matched
+// after it). The prolog includes the newline that terminates its last line. If the content has no prolog, the
+// returned prolog is empty.
+func splitProlog(content string) (string, string) {
+	end := 0
+	if strings.HasPrefix(content, utf8BOM) {
+		end = len(utf8BOM)
+	}
+	for lineNum := 0; lineNum < 2; lineNum++ {
+		lineEnd := strings.Index(content[end:], "\n")
+		if lineEnd == -1 {
+			// prolog lines must be terminated by a newline
+			break
+		}
+		line := content[end : end+lineEnd]
+		if !isPrologLine(line, lineNum) {
+			break
+		}
+		end += lineEnd + 1
+	}
+	return content[:end], content[end:]
+}
+
+func isPrologLine(line string, lineNum int) bool {
+	if lineNum == 0 && (strings.HasPrefix(line, "#!") || (strings.HasPrefix(line, "<?xml") && strings.HasSuffix(strings.TrimSpace(line), "?>"))) {
+		return true
+	}
+	return codingRegexp.MatchString(line)
+}
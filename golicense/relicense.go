@@ -0,0 +1,186 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RelicenseResult describes the files processed by RelicenseFiles.
+type RelicenseResult struct {
+	// Relicensed are the files whose header was replaced.
+	Relicensed []string
+	// Unchanged are the files that already had the new header.
+	Unchanged []string
+	// Unmatched are the files that had neither the old nor the new header.
+	Unmatched []string
+}
+
+// Print prints a summary of the result to the provided writer, followed by the files that had neither header.
+func (r RelicenseResult) Print(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "%s relicensed, %s already had the new header\n", fileCount(len(r.Relicensed)), fileCount(len(r.Unchanged)))
+	if len(r.Unmatched) == 0 {
+		return
+	}
+	parts := append([]string{fmt.Sprintf("%s matched neither header:", fileCount(len(r.Unmatched)))}, r.Unmatched...)
+	_, _ = fmt.Fprintln(w, strings.Join(parts, "\n\t"))
+}
+
+func fileCount(n int) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+// RunRelicense relicenses the provided files using RelicenseFiles and prints a summary of the result to stdout.
+func RunRelicense(files []string, from, to Licenser, projectParam ProjectParam, stdout io.Writer) error {
+	result, err := RelicenseFiles(files, from, to, projectParam)
+	if err != nil {
+		return err
+	}
+	result.Print(stdout)
+	return nil
+}
+
+// RelicenseFiles replaces the header of the "from" Licenser with the header of the "to" Licenser in the provided files.
+// Files that are excluded by projectParam or that are not of a processed file type are ignored, and both headers are
+// rendered in the comment style of the type of each file. If both headers contain "{{YEAR}}", the year of the replaced
+// header is kept. Files that already have the "to" header are not modified.
+//
+// The files are processed in a single pass: all of the files are read, relicensed and (if projectParam.ParseCheck is
+// true) checked in memory before any file is written, so no file is modified if any file cannot be read or fails the
+// parse check. If a file cannot be written, the files that were already written are restored to their original content
+// and an error is returned.
+func RelicenseFiles(files []string, from, to Licenser, projectParam ProjectParam) (RelicenseResult, error) {
+	type relicensedFile struct {
+		path       string
+		perm       os.FileMode
+		content    string
+		newContent string
+	}
+
+	var result RelicenseResult
+	var relicensed []relicensedFile
+	for _, f := range files {
+		if !isIncluded(f, projectParam) {
+			continue
+		}
+		fi, err := os.Stat(f)
+		if err != nil {
+			return RelicenseResult{}, errors.Wrapf(err, "failed to stat %s", f)
+		}
+		bytes, err := os.ReadFile(f)
+		if err != nil {
+			return RelicenseResult{}, errors.Wrapf(err, "failed to read %s", f)
+		}
+		fileType, _ := fileTypeForFile(f, projectParam)
+		content := string(bytes)
+		newContent, status := relicenseContent(content, licenserForFileType(from, fileType, projectParam), licenserForFileType(to, fileType, projectParam))
+		switch status {
+		case relicenseStatusRelicensed:
+			if projectParam.ParseCheck {
+				if err := checkModifiedFile(f, content, newContent); err != nil {
+					return RelicenseResult{}, errors.Wrapf(err, "parse check failed for %s (no files modified)", f)
+				}
+			}
+			relicensed = append(relicensed, relicensedFile{path: f, perm: fi.Mode(), content: content, newContent: newContent})
+			result.Relicensed = append(result.Relicensed, f)
+		case relicenseStatusUnchanged:
+			result.Unchanged = append(result.Unchanged, f)
+		default:
+			result.Unmatched = append(result.Unmatched, f)
+		}
+	}
+
+	for i, f := range relicensed {
+		if err := writeFileAtomic(f.path, []byte(f.newContent), f.perm); err != nil {
+			err = errors.Wrapf(err, "failed to write file %s with new license", f.path)
+			for _, written := range relicensed[:i] {
+				if restoreErr := writeFileAtomic(written.path, []byte(written.content), written.perm); restoreErr != nil {
+					return RelicenseResult{}, errors.Wrapf(err, "failed to restore original content of %s (%v)", written.path, restoreErr)
+				}
+			}
+			return RelicenseResult{}, err
+		}
+	}
+
+	sort.Strings(result.Relicensed)
+	sort.Strings(result.Unchanged)
+	sort.Strings(result.Unmatched)
+	return result, nil
+}
+
+type relicenseStatus int
+
+const (
+	relicenseStatusUnmatched relicenseStatus = iota
+	relicenseStatusUnchanged
+	relicenseStatusRelicensed
+)
+
+// relicenseContent returns the provided content with the header of the "from" Licenser replaced by the header of the
+// "to" Licenser, keeping the year of the "from" header if both headers contain "{{YEAR}}".
+func relicenseContent(content string, from, to Licenser) (string, relicenseStatus) {
+	if to.Matches(content) {
+		return content, relicenseStatusUnchanged
+	}
+	if !from.Matches(content) {
+		return content, relicenseStatusUnmatched
+	}
+	if y, ok := from.(yearSetter); ok {
+		if year, ok := y.matchedYear(content); ok {
+			if y, ok := to.(yearSetter); ok {
+				to = y.withYear(year)
+			}
+		}
+	}
+	return to.Add(from.Remove(content)), relicenseStatusRelicensed
+}
+
+// yearSetter is implemented by Licensers whose header can be added with a specific year.
+type yearSetter interface {
+	// matchedYear returns the year that matches the first "{{YEAR}}" of the header if the provided content starts with
+	// the header. Returns false if the content does not start with the header or the header does not contain
+	// "{{YEAR}}".
+	matchedYear(content string) (string, bool)
+	// withYear returns a Licenser for the same header that uses the provided year in place of "{{YEAR}}" when the
+	// license is added.
+	withYear(year string) Licenser
+}
+
+func (l *licenserImpl) matchedYear(content string) (string, bool) {
+	_, content = splitProlog(content)
+	content = content[l.licenseStart(content):]
+	candidates := append([]*licenserImpl{l}, l.alternates...)
+	for _, accepted := range l.accepted {
+		candidates = append(candidates, accepted)
+		candidates = append(candidates, accepted.alternates...)
+	}
+	for _, candidate := range candidates {
+		matchLoc, ok := candidate.match(content)
+		if !ok {
+			continue
+		}
+		group := candidate.matchRegexp.SubexpIndex("year")
+		if group < 0 {
+			return "", false
+		}
+		return content[matchLoc[2*group]:matchLoc[2*group+1]], true
+	}
+	return "", false
+}
+
+func (l *licenserImpl) withYear(year string) Licenser {
+	withYear := *l
+	withYear.newLicenseHeader = strings.ReplaceAll(l.template, "{{YEAR}}", year)
+	return &withYear
+}
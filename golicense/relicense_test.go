@@ -0,0 +1,113 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/palantir/pkg/matcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelicenseFiles(t *testing.T) {
+	from := golicense.NewLicenser("// Copyright {{YEAR}} Old Co.")
+	to := golicense.NewLicenser("// Copyright {{YEAR}} New Co.\n// SPDX-License-Identifier: MIT")
+
+	t.Run("headers replaced keeping year", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		files := writeFiles(t, tmpDir, map[string]string{
+			"block.go":     "/*\nCopyright 2013 Old Co.\n*/\n\npackage foo\n",
+			"excluded.go":  "package foo\n",
+			"new.go":       "// Copyright 2019 New Co.\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+			"none.go":      "package foo\n",
+			"old.go":       "// Copyright 2015 Old Co.\n\npackage foo\n",
+			"script.sh":    "#!/bin/sh\n# Copyright 2014 Old Co.\n\necho foo\n",
+			"unrelated.go": "// Copyright 2015 Other Co.\n\npackage foo\n",
+		})
+		projectParam := golicense.ProjectParam{
+			Licenser: from,
+			Exclude:  matcher.Name("excluded.go"),
+			FileTypes: []golicense.FileTypeParam{
+				{
+					Name:         "shell",
+					Matcher:      matcher.Name(`.*\.sh`),
+					CommentStyle: golicense.HashStyle,
+				},
+			},
+		}
+
+		result, err := golicense.RelicenseFiles(files, from, to, projectParam)
+		require.NoError(t, err)
+		assert.Equal(t, golicense.RelicenseResult{
+			Relicensed: []string{"block.go", "old.go", "script.sh"},
+			Unchanged:  []string{"new.go"},
+			Unmatched:  []string{"none.go", "unrelated.go"},
+		}, result)
+
+		for path, want := range map[string]string{
+			"block.go":     "// Copyright 2013 New Co.\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+			"excluded.go":  "package foo\n",
+			"new.go":       "// Copyright 2019 New Co.\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+			"old.go":       "// Copyright 2015 New Co.\n// SPDX-License-Identifier: MIT\n\npackage foo\n",
+			"script.sh":    "#!/bin/sh\n# Copyright 2014 New Co.\n# SPDX-License-Identifier: MIT\n\necho foo\n",
+			"unrelated.go": "// Copyright 2015 Other Co.\n\npackage foo\n",
+		} {
+			content, err := os.ReadFile(filepath.Join(tmpDir, path))
+			require.NoError(t, err)
+			assert.Equal(t, want, string(content), path)
+		}
+
+		buf := &bytes.Buffer{}
+		result.Print(buf)
+		assert.Equal(t, "3 files relicensed, 1 file already had the new header\n2 files matched neither header:\n\tnone.go\n\tunrelated.go\n", buf.String())
+	})
+
+	t.Run("year of new header used if old header has no year", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		files := writeFiles(t, tmpDir, map[string]string{
+			"foo.go": "// Copyright Old Co.\n\npackage foo\n",
+		})
+		_, err := golicense.RelicenseFiles(files, golicense.NewLicenser("// Copyright Old Co."), golicense.NewLicenser("// Copyright 2020 New Co."), golicense.ProjectParam{})
+		require.NoError(t, err)
+		content, err := os.ReadFile(filepath.Join(tmpDir, "foo.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "// Copyright 2020 New Co.\n\npackage foo\n", string(content))
+	})
+
+	t.Run("no files written if a file fails the parse check", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		oldWd := chdir(t, tmpDir)
+		defer oldWd()
+
+		original := map[string]string{
+			"a.go": "// Copyright 2015 Old Co.\n\npackage foo\n",
+			"b.go": "// Copyright 2015 Old Co.\n\n// +build linux\n\npackage foo\n",
+		}
+		writeFiles(t, tmpDir, original)
+		// a.go is relicensed before b.go fails the check
+		_, err := golicense.RelicenseFiles([]string{"a.go", "b.go"}, from, golicense.NewLicenser("/* Copyright {{YEAR}} New Co. */"), golicense.ProjectParam{
+			ParseCheck: true,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parse check failed for b.go (no files modified)")
+
+		for path, want := range original {
+			content, err := os.ReadFile(filepath.Join(tmpDir, path))
+			require.NoError(t, err)
+			assert.Equal(t, want, string(content), path)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/palantir/go-license/golicense/spdx"
+)
+
+// spdxTag is the tag of the line of a header that specifies the SPDX license expression of the file.
+const spdxTag = "SPDX-License-Identifier:"
+
+// SPDXHeader returns the plain text of the header for the provided SPDX license expression: a
+// "SPDX-License-Identifier: <expression>" line, preceded by a "Copyright {{YEAR}} <holder>" line if holder is
+// non-empty. The returned text is not a comment: use CommentStyle.Render to render it in a comment style. Returns an
+// error if the expression is not a valid SPDX license expression.
+func SPDXHeader(expression, holder string) (string, error) {
+	expr, err := spdx.Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	header := spdxTag + " " + expr.String()
+	if holder = strings.TrimSpace(holder); holder != "" {
+		header = "Copyright {{YEAR}} " + holder + "\n" + header
+	}
+	return header, nil
+}
+
+// spdxLinePattern returns the regular expression that matches the provided line of a license and the SPDX license
+// expression that it specifies. The expression in the line is matched by a capturing group named "spdx" so that any
+// equivalent expression can be accepted. Returns false if the line does not specify a valid SPDX license expression.
+func spdxLinePattern(line string) (string, *spdx.Expression, bool) {
+	idx := strings.Index(line, spdxTag)
+	if idx < 0 {
+		return "", nil, false
+	}
+	expr, err := spdx.Parse(line[idx+len(spdxTag):])
+	if err != nil {
+		return "", nil, false
+	}
+	return regexp.QuoteMeta(line[:idx+len(spdxTag)]) + `[ \t]*(?P<spdx>\S[^\r\n]*?)`, expr, true
+}
+
+// spdxExpressionsMatch returns true if the SPDX license expressions captured by the "spdx" groups of the provided
+// regular expression (whose submatch indices in content are provided) are equivalent to the provided expressions.
+func spdxExpressionsMatch(content string, re *regexp.Regexp, submatches []int, exprs []*spdx.Expression) bool {
+	i := 0
+	for group, name := range re.SubexpNames() {
+		if name != "spdx" {
+			continue
+		}
+		start, end := submatches[2*group], submatches[2*group+1]
+		if start < 0 || i >= len(exprs) {
+			return false
+		}
+		got, err := spdx.Parse(content[start:end])
+		if err != nil || !got.Equivalent(exprs[i]) {
+			return false
+		}
+		i++
+	}
+	return true
+}
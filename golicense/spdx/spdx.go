@@ -0,0 +1,291 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+// Package spdx parses SPDX license expressions such as "Apache-2.0 OR MIT" and determines whether two expressions are
+// equivalent. License and exception identifiers are validated against an embedded copy of the SPDX license list that
+// contains the commonly used licenses and exceptions.
+package spdx
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	//go:embed licenses.txt
+	licensesTxt string
+	//go:embed exceptions.txt
+	exceptionsTxt string
+
+	// maps the lowercase form of each identifier to its canonical form (identifiers are case-insensitive)
+	licenseIDs   = parseIDList(licensesTxt)
+	exceptionIDs = parseIDList(exceptionsTxt)
+)
+
+func parseIDList(list string) map[string]string {
+	ids := make(map[string]string)
+	for _, id := range strings.Fields(list) {
+		ids[strings.ToLower(id)] = id
+	}
+	return ids
+}
+
+// Expression is a parsed SPDX license expression.
+type Expression struct {
+	// operator of a compound expression ("AND" or "OR"). Empty for a simple expression.
+	op       string
+	operands []*Expression
+
+	// license identifier or license reference of a simple expression, including the "+" suffix if present
+	license string
+	// exception identifier of a simple expression with a "WITH" clause
+	exception string
+}
+
+// Parse parses the provided SPDX license expression. License and exception identifiers are case-insensitive and must
+// be in the embedded SPDX license list unless they are license references ("LicenseRef-..." or
+// "DocumentRef-...:LicenseRef-...") or addition references ("AdditionRef-..."). The operators "AND", "OR" and "WITH"
+// must be uppercase.
+func Parse(expr string) (*Expression, error) {
+	p := &parser{tokens: tokenize(expr)}
+	if len(p.tokens) == 0 {
+		return nil, errors.Errorf("invalid SPDX license expression %q: expression is empty", expr)
+	}
+	e, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = errors.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid SPDX license expression %q", expr)
+	}
+	return e, nil
+}
+
+// String returns the expression with canonical identifiers, single spaces between tokens and only the parentheses
+// that are required by operator precedence. The order of the operands is preserved.
+func (e *Expression) String() string {
+	if e.op == "" {
+		if e.exception != "" {
+			return e.license + " WITH " + e.exception
+		}
+		return e.license
+	}
+	parts := make([]string, len(e.operands))
+	for i, operand := range e.operands {
+		parts[i] = operand.String()
+		// "AND" takes precedence over "OR", so an "OR" operand of an "AND" must be parenthesized
+		if e.op == "AND" && operand.op == "OR" {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+e.op+" ")
+}
+
+// Equivalent returns true if the provided expression is equivalent to this one. Expressions are equivalent if they
+// are the same after "AND" and "OR" operands are flattened, sorted and deduplicated, so "MIT OR Apache-2.0" is
+// equivalent to "Apache-2.0 OR MIT" and "(MIT AND ISC) AND MIT" is equivalent to "ISC AND MIT".
+func (e *Expression) Equivalent(other *Expression) bool {
+	return e.normalize().String() == other.normalize().String()
+}
+
+// normalize returns the normal form of the expression used to determine equivalence.
+func (e *Expression) normalize() *Expression {
+	if e.op == "" {
+		return e
+	}
+	seen := make(map[string]struct{})
+	var operands []*Expression
+	var add func(operand *Expression)
+	add = func(operand *Expression) {
+		operand = operand.normalize()
+		if operand.op == e.op {
+			// operators are associative
+			for _, nested := range operand.operands {
+				add(nested)
+			}
+			return
+		}
+		if _, ok := seen[operand.String()]; !ok {
+			seen[operand.String()] = struct{}{}
+			operands = append(operands, operand)
+		}
+	}
+	for _, operand := range e.operands {
+		add(operand)
+	}
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	sort.Slice(operands, func(i, j int) bool {
+		return operands[i].String() < operands[j].String()
+	})
+	return &Expression{op: e.op, operands: operands}
+}
+
+// tokenize splits the provided expression into identifiers, operators and parentheses.
+func tokenize(expr string) []string {
+	var tokens []string
+	for _, field := range strings.Fields(expr) {
+		start := 0
+		for i, r := range field {
+			if r == '(' || r == ')' {
+				if i > start {
+					tokens = append(tokens, field[start:i])
+				}
+				tokens = append(tokens, string(r))
+				start = i + 1
+			}
+		}
+		if start < len(field) {
+			tokens = append(tokens, field[start:])
+		}
+	}
+	return tokens
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) next() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	return tok, true
+}
+
+func (p *parser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// parseOr parses operands separated by "OR", which has the lowest precedence.
+func (p *parser) parseOr() (*Expression, error) {
+	return p.parseCompound("OR", p.parseAnd)
+}
+
+// parseAnd parses operands separated by "AND".
+func (p *parser) parseAnd() (*Expression, error) {
+	return p.parseCompound("AND", p.parseTerm)
+}
+
+func (p *parser) parseCompound(op string, parseOperand func() (*Expression, error)) (*Expression, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expression{first}
+	for p.peek() == op {
+		p.pos++
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &Expression{op: op, operands: operands}, nil
+}
+
+// parseTerm parses a parenthesized expression or a license with an optional "WITH" clause.
+func (p *parser) parseTerm() (*Expression, error) {
+	tok, ok := p.next()
+	if !ok {
+		return nil, errors.Errorf("unexpected end of expression")
+	}
+	switch tok {
+	case "(":
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok, _ := p.next(); tok != ")" {
+			return nil, errors.Errorf(`missing ")"`)
+		}
+		return e, nil
+	case ")", "AND", "OR", "WITH":
+		return nil, errors.Errorf("unexpected %q", tok)
+	}
+
+	license, err := licenseID(tok)
+	if err != nil {
+		return nil, err
+	}
+	e := &Expression{license: license}
+	if p.peek() == "WITH" {
+		p.pos++
+		tok, ok := p.next()
+		if !ok {
+			return nil, errors.Errorf(`missing exception after "WITH"`)
+		}
+		if e.exception, err = exceptionID(tok); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// licenseID returns the canonical form of the provided license identifier or license reference.
+func licenseID(tok string) (string, error) {
+	if isLicenseRef(tok) {
+		return tok, nil
+	}
+	id, orLater := strings.TrimSuffix(tok, "+"), strings.HasSuffix(tok, "+")
+	canonical, ok := licenseIDs[strings.ToLower(id)]
+	if !ok {
+		// deprecated identifiers such as "GPL-2.0+" are in the list with their suffix
+		if canonical, ok = licenseIDs[strings.ToLower(tok)]; ok {
+			return canonical, nil
+		}
+		return "", errors.Errorf("unknown license identifier %q", tok)
+	}
+	if orLater {
+		canonical += "+"
+	}
+	return canonical, nil
+}
+
+// exceptionID returns the canonical form of the provided exception identifier or addition reference.
+func exceptionID(tok string) (string, error) {
+	if strings.HasPrefix(tok, "AdditionRef-") && isIDString(strings.TrimPrefix(tok, "AdditionRef-")) {
+		return tok, nil
+	}
+	canonical, ok := exceptionIDs[strings.ToLower(tok)]
+	if !ok {
+		return "", errors.Errorf("unknown license exception identifier %q", tok)
+	}
+	return canonical, nil
+}
+
+// isLicenseRef returns true if the provided token is a license reference of the form "LicenseRef-<id>" or
+// "DocumentRef-<id>:LicenseRef-<id>".
+func isLicenseRef(tok string) bool {
+	if doc, ref, ok := strings.Cut(tok, ":"); ok {
+		return strings.HasPrefix(doc, "DocumentRef-") && isIDString(strings.TrimPrefix(doc, "DocumentRef-")) && isLicenseRef(ref)
+	}
+	return strings.HasPrefix(tok, "LicenseRef-") && isIDString(strings.TrimPrefix(tok, "LicenseRef-"))
+}
+
+// isIDString returns true if the provided value is a non-empty string of letters, digits, "-" and ".".
+func isIDString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
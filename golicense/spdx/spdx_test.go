@@ -0,0 +1,116 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package spdx_test
+
+import (
+	"testing"
+
+	"github.com/palantir/go-license/golicense/spdx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "single license",
+			expr: "Apache-2.0",
+			want: "Apache-2.0",
+		},
+		{
+			name: "identifiers are case-insensitive",
+			expr: "apache-2.0 OR mit",
+			want: "Apache-2.0 OR MIT",
+		},
+		{
+			name: "unnecessary parentheses and whitespace removed",
+			expr: " ( MIT  AND ISC )  OR(Apache-2.0)",
+			want: "MIT AND ISC OR Apache-2.0",
+		},
+		{
+			name: "parentheses required by precedence kept",
+			expr: "MIT AND (ISC OR Apache-2.0)",
+			want: "MIT AND (ISC OR Apache-2.0)",
+		},
+		{
+			name: "exception and or-later suffix",
+			expr: "GPL-2.0-or-later WITH classpath-exception-2.0 OR LGPL-2.1+",
+			want: "GPL-2.0-or-later WITH Classpath-exception-2.0 OR LGPL-2.1+",
+		},
+		{
+			name: "license references",
+			expr: "LicenseRef-Proprietary OR DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2",
+			want: "LicenseRef-Proprietary OR DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2",
+		},
+		{
+			name:    "unknown license",
+			expr:    "Apache-2.0 OR Foo-1.0",
+			wantErr: `invalid SPDX license expression "Apache-2.0 OR Foo-1.0": unknown license identifier "Foo-1.0"`,
+		},
+		{
+			name:    "unknown exception",
+			expr:    "GPL-2.0-only WITH Foo-exception",
+			wantErr: `invalid SPDX license expression "GPL-2.0-only WITH Foo-exception": unknown license exception identifier "Foo-exception"`,
+		},
+		{
+			name:    "lowercase operator",
+			expr:    "MIT or Apache-2.0",
+			wantErr: `invalid SPDX license expression "MIT or Apache-2.0": unexpected "or"`,
+		},
+		{
+			name:    "missing operand",
+			expr:    "MIT OR",
+			wantErr: `invalid SPDX license expression "MIT OR": unexpected end of expression`,
+		},
+		{
+			name:    "unbalanced parentheses",
+			expr:    "(MIT OR Apache-2.0",
+			wantErr: `invalid SPDX license expression "(MIT OR Apache-2.0": missing ")"`,
+		},
+		{
+			name:    "empty",
+			expr:    " ",
+			wantErr: `invalid SPDX license expression " ": expression is empty`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := spdx.Parse(tc.expr)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{"Apache-2.0 OR MIT", "MIT OR Apache-2.0", true},
+		{"Apache-2.0 OR MIT", "(mit) OR apache-2.0", true},
+		{"MIT AND (ISC AND MIT)", "ISC AND MIT", true},
+		{"MIT OR ISC OR Apache-2.0", "(Apache-2.0 OR ISC) OR MIT", true},
+		{"MIT AND ISC OR Apache-2.0", "Apache-2.0 OR ISC AND MIT", true},
+		{"Apache-2.0 OR MIT", "Apache-2.0 AND MIT", false},
+		{"MIT AND (ISC OR Apache-2.0)", "MIT AND ISC OR Apache-2.0", false},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-only", false},
+		{"GPL-2.0-only", "GPL-2.0-or-later", false},
+	} {
+		a, err := spdx.Parse(tc.a)
+		require.NoError(t, err)
+		b, err := spdx.Parse(tc.b)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, a.Equivalent(b), "%q and %q", tc.a, tc.b)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"strings"
+
+	"github.com/palantir/pkg/matcher"
+	"github.com/pkg/errors"
+)
+
+// CommentStyle describes the comment syntax that is used to render a license header in a type of source file.
+type CommentStyle struct {
+	// LinePrefix is the prefix of a line comment (for example, "//" or "#"). If non-empty, every line of the header
+	// is rendered as a line comment.
+	LinePrefix string
+	// BlockStart and BlockEnd delimit a block comment (for example, "/*" and "*/"). Used only if LinePrefix is empty,
+	// in which case the header is rendered as a single block comment with the delimiters on their own lines.
+	BlockStart string
+	BlockEnd   string
+}
+
+var (
+	// SlashStyle renders headers as "//" line comments. This is the style used for Go files.
+	SlashStyle = CommentStyle{LinePrefix: "//"}
+	// HashStyle renders headers as "#" line comments (shell scripts, Dockerfiles, YAML, etc.).
+	HashStyle = CommentStyle{LinePrefix: "#"}
+	// DashStyle renders headers as "--" line comments (SQL, Lua, etc.).
+	DashStyle = CommentStyle{LinePrefix: "--"}
+	// BlockStyle renders headers as a single "/* */" block comment.
+	BlockStyle = CommentStyle{BlockStart: "/*", BlockEnd: "*/"}
+	// TemplateStyle renders headers as a single text/template comment. The closing delimiter trims the whitespace that
+	// follows it so that the header does not change the output of the template.
+	TemplateStyle = CommentStyle{BlockStart: "{{/*", BlockEnd: "*/ -}}"}
+)
+
+// ParseCommentStyle returns the CommentStyle for the provided value, which is either the line comment prefix ("//",
+// "#" or "--") or the block comment delimiters separated by a space ("/* */" or "{{/* */}}").
+func ParseCommentStyle(style string) (CommentStyle, error) {
+	switch style {
+	case "//":
+		return SlashStyle, nil
+	case "#":
+		return HashStyle, nil
+	case "--":
+		return DashStyle, nil
+	case "/* */":
+		return BlockStyle, nil
+	case "{{/* */}}":
+		return TemplateStyle, nil
+	default:
+		return CommentStyle{}, errors.Errorf(`invalid comment style %q: must be one of "//", "#", "--", "/* */" or "{{/* */}}"`, style)
+	}
+}
+
+func (s CommentStyle) String() string {
+	switch {
+	case s.LinePrefix != "":
+		return s.LinePrefix
+	case s == TemplateStyle:
+		return "{{/* */}}"
+	default:
+		return s.BlockStart + " " + s.BlockEnd
+	}
+}
+
+// Render renders the provided plain header text as a comment in this style. Any trailing newlines in the text are
+// preserved after the rendered comment.
+func (s CommentStyle) Render(text string) string {
+	body, trailing := splitTrailingNewlines(text)
+	lines := strings.Split(body, "\n")
+	if s.LinePrefix != "" {
+		for i, line := range lines {
+			if line == "" {
+				lines[i] = s.LinePrefix
+			} else {
+				lines[i] = s.LinePrefix + " " + line
+			}
+		}
+		return strings.Join(lines, "\n") + trailing
+	}
+	return s.BlockStart + "\n" + body + "\n" + s.BlockEnd + trailing
+}
+
+// Strip returns the plain header text for the provided header rendered in this style. It is the inverse of Render.
+// Returns false if the header is not a comment in this style.
+func (s CommentStyle) Strip(header string) (string, bool) {
+	body, trailing := splitTrailingNewlines(header)
+	if body == "" {
+		return "", false
+	}
+	if s.LinePrefix != "" {
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			if !strings.HasPrefix(line, s.LinePrefix) {
+				return "", false
+			}
+			line = strings.TrimPrefix(line, s.LinePrefix)
+			if line != "" && !strings.HasPrefix(line, " ") {
+				return "", false
+			}
+			lines[i] = strings.TrimPrefix(line, " ")
+		}
+		return strings.Join(lines, "\n") + trailing, true
+	}
+	if !strings.HasPrefix(body, s.BlockStart+"\n") || !strings.HasSuffix(body, "\n"+s.BlockEnd) || len(body) < len(s.BlockStart)+len(s.BlockEnd)+2 {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(body, s.BlockStart+"\n"), "\n"+s.BlockEnd) + trailing, true
+}
+
+// splitTrailingNewlines splits the provided text into its content and its trailing newlines.
+func splitTrailingNewlines(text string) (string, string) {
+	body := strings.TrimRight(text, "\n")
+	return body, text[len(body):]
+}
+
+// FileTypeParam specifies a type of file that is processed and the comment style that is used for its license header.
+type FileTypeParam struct {
+	// Name is the identifier of the file type. Must be unique.
+	Name string
+
+	// Matcher matches the files of this type.
+	Matcher matcher.Matcher
+
+	// CommentStyle is the style in which the license header is rendered for files of this type.
+	CommentStyle CommentStyle
+}
+
+// goFileType is the file type for "*.go" files, which are always processed.
+var goFileType = FileTypeParam{
+	Name:         "go",
+	Matcher:      matcher.Name(`.*\.go`),
+	CommentStyle: SlashStyle,
+}
+
+// builtinFileTypes are the file types of the non-Go files that are commonly part of Go packages. The configuration
+// processes them by default.
+var builtinFileTypes = []FileTypeParam{
+	{
+		// Plan 9 assembly
+		Name:         "asm",
+		Matcher:      matcher.Name(`.*\.s`),
+		CommentStyle: SlashStyle,
+	},
+	{
+		// cgo C sources and headers
+		Name:         "c",
+		Matcher:      matcher.Name(`.*\.c`, `.*\.h`),
+		CommentStyle: SlashStyle,
+	},
+	{
+		Name:         "proto",
+		Matcher:      matcher.Name(`.*\.proto`),
+		CommentStyle: SlashStyle,
+	},
+	{
+		// text/template and html/template files
+		Name:         "tmpl",
+		Matcher:      matcher.Name(`.*\.tmpl`, `.*\.gotmpl`),
+		CommentStyle: TemplateStyle,
+	},
+}
+
+// BuiltinFileType returns the built-in file type with the provided name. The built-in file types are "asm" ("*.s"),
+// "c" ("*.c" and "*.h"), "proto" ("*.proto") and "tmpl" ("*.tmpl" and "*.gotmpl"). Returns false if there is no
+// built-in file type with the provided name.
+func BuiltinFileType(name string) (FileTypeParam, bool) {
+	for _, fileType := range builtinFileTypes {
+		if fileType.Name == name {
+			return fileType, true
+		}
+	}
+	return FileTypeParam{}, false
+}
+
+// BuiltinFileTypes returns the built-in file types (see BuiltinFileType).
+func BuiltinFileTypes() []FileTypeParam {
+	return append([]FileTypeParam(nil), builtinFileTypes...)
+}
+
+// fileTypeForFile returns the file type of the provided file. "*.go" files are always of the Go file type; otherwise,
+// the first file type in projectParam that matches the file is returned. Returns false if no file type matches.
+func fileTypeForFile(file string, projectParam ProjectParam) (FileTypeParam, bool) {
+	if goFileType.Matcher.Match(file) {
+		return goFileType, true
+	}
+	for _, fileType := range projectParam.FileTypes {
+		if fileType.Matcher != nil && fileType.Matcher.Match(file) {
+			return fileType, true
+		}
+	}
+	return FileTypeParam{}, false
+}
+
+// restyler is implemented by Licensers whose header can be rendered in a different comment style.
+type restyler interface {
+	// withCommentStyle returns a Licenser for the same header text rendered in the provided style.
+	withCommentStyle(style CommentStyle) Licenser
+}
+
+// licenserForStyle returns a Licenser for the header of the provided Licenser rendered in the provided style. Headers
+// are specified in the "//" style, so the provided Licenser is returned unmodified for that style. Licensers that are
+// empty or that do not support rendering their header in other styles are also returned unmodified.
+func licenserForStyle(licenser Licenser, style CommentStyle) Licenser {
+	if style == SlashStyle || licenser.Empty() {
+		return licenser
+	}
+	if r, ok := licenser.(restyler); ok {
+		return r.withCommentStyle(style)
+	}
+	return licenser
+}
+
+// converter is implemented by Licensers that match alternate renderings of their header.
+type converter interface {
+	// withConvert returns a Licenser that matches only the canonical rendering of the header and that replaces an
+	// alternate rendering with the canonical one when adding the license.
+	withConvert() Licenser
+}
+
+// licenserForFileType returns the Licenser that should be used for files of the provided type given the Licenser that
+// applies to them based on their path.
+func licenserForFileType(licenser Licenser, fileType FileTypeParam, projectParam ProjectParam) Licenser {
+	licenser = licenserForStyle(licenser, fileType.CommentStyle)
+	if projectParam.ConvertCommentStyle {
+		if c, ok := licenser.(converter); ok {
+			licenser = c.withConvert()
+		}
+	}
+	if projectParam.Strict {
+		if s, ok := licenser.(strictSetter); ok {
+			licenser = s.withStrict()
+		}
+	}
+	return licenser
+}
+
+// headerText returns the plain text of the provided header. The header is expected to be a "//" or "/* */" comment: if
+// it is neither, the header is considered to be plain text already.
+func headerText(header string) string {
+	if text, ok := SlashStyle.Strip(header); ok {
+		return text
+	}
+	if text, ok := BlockStyle.Strip(header); ok {
+		return text
+	}
+	return header
+}
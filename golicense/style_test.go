@@ -0,0 +1,115 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommentStyleRender(t *testing.T) {
+	text := "Copyright 2016 Palantir Technologies, Inc.\n\nLicense content.\n"
+	for _, tc := range []struct {
+		style golicense.CommentStyle
+		want  string
+	}{
+		{
+			style: golicense.SlashStyle,
+			want:  "// Copyright 2016 Palantir Technologies, Inc.\n//\n// License content.\n",
+		},
+		{
+			style: golicense.HashStyle,
+			want:  "# Copyright 2016 Palantir Technologies, Inc.\n#\n# License content.\n",
+		},
+		{
+			style: golicense.DashStyle,
+			want:  "-- Copyright 2016 Palantir Technologies, Inc.\n--\n-- License content.\n",
+		},
+		{
+			style: golicense.BlockStyle,
+			want:  "/*\nCopyright 2016 Palantir Technologies, Inc.\n\nLicense content.\n*/\n",
+		},
+	} {
+		t.Run(tc.style.String(), func(t *testing.T) {
+			rendered := tc.style.Render(text)
+			assert.Equal(t, tc.want, rendered)
+
+			stripped, ok := tc.style.Strip(rendered)
+			assert.True(t, ok)
+			assert.Equal(t, text, stripped)
+		})
+	}
+}
+
+func TestBuiltinFileTypes(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		files     []string
+		wantStyle golicense.CommentStyle
+	}{
+		{"asm", []string{"foo_amd64.s"}, golicense.SlashStyle},
+		{"c", []string{"foo.c", "foo.h"}, golicense.SlashStyle},
+		{"proto", []string{"foo.proto"}, golicense.SlashStyle},
+		{"tmpl", []string{"foo.tmpl", "foo.gotmpl"}, golicense.TemplateStyle},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fileType, ok := golicense.BuiltinFileType(tc.name)
+			require.True(t, ok)
+			assert.Equal(t, tc.wantStyle, fileType.CommentStyle)
+			for _, f := range tc.files {
+				assert.True(t, fileType.Matcher.Match(f), "expected %s to match", f)
+			}
+			assert.False(t, fileType.Matcher.Match("foo.go"))
+		})
+	}
+
+	_, ok := golicense.BuiltinFileType("unknown")
+	assert.False(t, ok)
+}
+
+func TestTemplateStyleDoesNotChangeOutput(t *testing.T) {
+	licenser := golicense.NewLicenser("// Copyright 2016 Palantir Technologies, Inc.\n")
+	fileType, ok := golicense.BuiltinFileType("tmpl")
+	require.True(t, ok)
+	projectParam := golicense.ProjectParam{
+		Licenser:  licenser,
+		FileTypes: []golicense.FileTypeParam{fileType},
+	}
+
+	content := "Hello, {{.}}!\n"
+	licensed, status := golicense.LicenseContent("foo.tmpl", []byte(content), projectParam)
+	require.Equal(t, golicense.StatusModified, status)
+	assert.Equal(t, "{{/*\nCopyright 2016 Palantir Technologies, Inc.\n*/ -}}\n\nHello, {{.}}!\n", string(licensed))
+
+	tmpl, err := template.New("foo").Parse(string(licensed))
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	require.NoError(t, tmpl.Execute(buf, "World"))
+	assert.Equal(t, "Hello, World!\n", buf.String())
+}
+
+func TestCommentStyleStripInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		style  golicense.CommentStyle
+		header string
+	}{
+		{"line without prefix", golicense.SlashStyle, "// Copyright\nLicense content.\n"},
+		{"prefix without space", golicense.SlashStyle, "//Copyright\n"},
+		{"different line style", golicense.HashStyle, "// Copyright\n"},
+		{"unterminated block", golicense.BlockStyle, "/*\nCopyright\n"},
+		{"empty", golicense.SlashStyle, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := tc.style.Strip(tc.header)
+			assert.False(t, ok)
+		})
+	}
+}
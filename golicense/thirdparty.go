@@ -0,0 +1,141 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ThirdPartyPolicy specifies how files that start with a third-party header are handled. A third-party header is a
+// leading comment that has the copyright of a holder other than the holder of the copyright in the license header.
+type ThirdPartyPolicy int
+
+const (
+	// ThirdPartyAddAbove adds the license header above the third-party header. This is the default.
+	ThirdPartyAddAbove ThirdPartyPolicy = iota
+	// ThirdPartySkip leaves files with a third-party header unmodified and considers them to have the correct license
+	// header.
+	ThirdPartySkip
+	// ThirdPartyAddBelow adds the license header below the third-party header, which remains at the start of the file.
+	// The license header is matched and removed below the third-party header.
+	ThirdPartyAddBelow
+	// ThirdPartyFail reports files with a third-party header as third-party code: verification fails for them, and
+	// adding licenses fails with an error.
+	ThirdPartyFail
+)
+
+// ParseThirdPartyPolicy returns the ThirdPartyPolicy for the provided value, which must be "add-above", "skip",
+// "add-below" or "fail". An empty value is ThirdPartyAddAbove.
+func ParseThirdPartyPolicy(policy string) (ThirdPartyPolicy, error) {
+	switch policy {
+	case "", "add-above":
+		return ThirdPartyAddAbove, nil
+	case "skip":
+		return ThirdPartySkip, nil
+	case "add-below":
+		return ThirdPartyAddBelow, nil
+	case "fail":
+		return ThirdPartyFail, nil
+	default:
+		return ThirdPartyAddAbove, errors.Errorf(`invalid third-party policy %q: must be one of "add-above", "skip", "add-below" or "fail"`, policy)
+	}
+}
+
+func (p ThirdPartyPolicy) String() string {
+	switch p {
+	case ThirdPartyAddAbove:
+		return "add-above"
+	case ThirdPartySkip:
+		return "skip"
+	case ThirdPartyAddBelow:
+		return "add-below"
+	case ThirdPartyFail:
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+// thirdPartyPolicySetter is implemented by Licensers that support a ThirdPartyPolicy.
+type thirdPartyPolicySetter interface {
+	// withThirdPartyPolicy returns a Licenser for the same header that handles third-party headers using the provided
+	// policy.
+	withThirdPartyPolicy(policy ThirdPartyPolicy) Licenser
+}
+
+// licenserForThirdPartyPolicy returns a Licenser for the header of the provided Licenser that handles third-party
+// headers using the provided policy. Licensers that do not support third-party policies are returned unmodified.
+func licenserForThirdPartyPolicy(licenser Licenser, policy ThirdPartyPolicy) Licenser {
+	if policy == ThirdPartyAddAbove || licenser.Empty() {
+		return licenser
+	}
+	if s, ok := licenser.(thirdPartyPolicySetter); ok {
+		return s.withThirdPartyPolicy(policy)
+	}
+	return licenser
+}
+
+// thirdPartyChecker is implemented by Licensers that report third-party code.
+type thirdPartyChecker interface {
+	// thirdPartyCopyright returns the copyright line of the third-party header of the provided content if the
+	// Licenser reports third-party code (its policy is ThirdPartyFail) and the content starts with a third-party
+	// header.
+	thirdPartyCopyright(content string) (string, bool)
+}
+
+// thirdPartyCode returns the copyright line of the third-party header of the provided content if the provided
+// Licenser reports the content as third-party code.
+func thirdPartyCode(licenser Licenser, content string) (string, bool) {
+	if c, ok := licenser.(thirdPartyChecker); ok {
+		return c.thirdPartyCopyright(content)
+	}
+	return "", false
+}
+
+func (l *licenserImpl) withThirdPartyPolicy(policy ThirdPartyPolicy) Licenser {
+	withPolicy := *l
+	withPolicy.thirdParty = policy
+	return &withPolicy
+}
+
+func (l *licenserImpl) thirdPartyCopyright(content string) (string, bool) {
+	if l.thirdParty != ThirdPartyFail {
+		return "", false
+	}
+	_, content = splitProlog(content)
+	_, copyright, ok := l.thirdPartyHeader(content)
+	return copyright, ok
+}
+
+// thirdPartyHeader returns the length of the third-party header that the provided content (without its prolog) starts
+// with, including the blank lines that follow it, and its copyright line. Returns false if the content does not start
+// with a third-party header or if it starts with the license.
+func (l *licenserImpl) thirdPartyHeader(content string) (int, string, bool) {
+	if _, ok := l.headerEnd(content); ok {
+		return 0, "", false
+	}
+	if _, ok := l.alternateEnd(content); ok && !l.convert {
+		return 0, "", false
+	}
+	if _, ok := l.acceptedEnd(content, true); ok {
+		// accepted headers may have the copyright of a previous holder, but they are not third-party headers
+		return 0, "", false
+	}
+	leading, ok := leadingComment(content, classificationStyles(l))
+	if !ok {
+		return 0, "", false
+	}
+	copyright := copyrightLine(leading.text)
+	if copyright == "" || sameHolder(copyright, l) {
+		return 0, "", false
+	}
+	end := leading.end
+	for startsWithBlankLine(content[end:]) {
+		end += strings.Index(content[end:], "\n") + 1
+	}
+	return end, copyright, true
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/palantir/go-license/golicense"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	thirdPartyHeader = `// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+`
+	thirdPartyContent = thirdPartyHeader + "\npackage foo\n"
+	ourHeader         = "// Copyright 2016 Palantir Technologies, Inc.\n"
+)
+
+func TestThirdPartyPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		policy      string
+		wantContent string
+		wantStatus  golicense.Status
+	}{
+		{
+			name:        "add-above",
+			policy:      "add-above",
+			wantContent: ourHeader + "\n" + thirdPartyContent,
+			wantStatus:  golicense.StatusModified,
+		},
+		{
+			name:        "skip",
+			policy:      "skip",
+			wantContent: thirdPartyContent,
+			wantStatus:  golicense.StatusUnchanged,
+		},
+		{
+			name:        "add-below",
+			policy:      "add-below",
+			wantContent: thirdPartyHeader + "\n" + ourHeader + "\npackage foo\n",
+			wantStatus:  golicense.StatusModified,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := golicense.ParseThirdPartyPolicy(tc.policy)
+			require.NoError(t, err)
+			projectParam := golicense.ProjectParam{
+				Licenser: golicense.NewLicenser("// Copyright {{YEAR}} Other Co.\n"),
+				CustomHeaders: []golicense.CustomHeaderParam{
+					{
+						Name:             "vendored",
+						Licenser:         golicense.NewLicenser(ourHeader),
+						ThirdPartyPolicy: policy,
+						IncludePaths:     []string{"foo"},
+					},
+				},
+			}
+
+			got, status := golicense.LicenseContent("foo/foo.go", []byte(thirdPartyContent), projectParam)
+			assert.Equal(t, tc.wantContent, string(got))
+			assert.Equal(t, tc.wantStatus, status)
+
+			// adding the license again does not modify the content
+			again, status := golicense.LicenseContent("foo/foo.go", got, projectParam)
+			assert.Equal(t, string(got), string(again))
+			assert.Equal(t, golicense.StatusUnchanged, status)
+
+			// removing the license restores the original content
+			removed, _ := golicense.UnlicenseContent("foo/foo.go", got, projectParam)
+			assert.Equal(t, thirdPartyContent, string(removed))
+		})
+	}
+
+	t.Run("add-below without blank line after third-party header", func(t *testing.T) {
+		projectParam := golicense.ProjectParam{
+			Licenser:         golicense.NewLicenser(ourHeader),
+			ThirdPartyPolicy: golicense.ThirdPartyAddBelow,
+		}
+		got, _ := golicense.LicenseContent("foo.go", []byte("/* Copyright 2009 Foo Co. */\npackage foo\n"), projectParam)
+		assert.Equal(t, "/* Copyright 2009 Foo Co. */\n\n"+ourHeader+"\npackage foo\n", string(got))
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		projectParam := golicense.ProjectParam{
+			Licenser:         golicense.NewLicenser(ourHeader),
+			ThirdPartyPolicy: golicense.ThirdPartyFail,
+		}
+		fsys := fstest.MapFS{
+			"foo.go": &fstest.MapFile{Data: []byte(thirdPartyContent)},
+			"bar.go": &fstest.MapFile{Data: []byte(ourHeader + "\npackage foo\n")},
+		}
+		buf := &bytes.Buffer{}
+		ok, err := golicense.VerifyFS(fsys, []string{"foo.go", "bar.go"}, projectParam, buf)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "1 file is third-party code (has the copyright of another holder):\n\tfoo.go (Copyright 2009 The Go Authors. All rights reserved.)\n", buf.String())
+
+		got, status := golicense.LicenseContent("foo.go", []byte(thirdPartyContent), projectParam)
+		assert.Equal(t, thirdPartyContent, string(got))
+		assert.Equal(t, golicense.StatusThirdParty, status)
+
+		_, err = golicense.LicenseFS(fsys, []string{"foo.go", "bar.go"}, projectParam)
+		assert.EqualError(t, err, "failed to process headers for default matcher: failed to process go files: foo.go is third-party code (Copyright 2009 The Go Authors. All rights reserved.)")
+
+		buf.Reset()
+		err = golicense.RunLicenseStdin("foo.go", projectParam, false, false, bytes.NewReader([]byte(thirdPartyContent)), buf)
+		assert.EqualError(t, err, "foo.go is third-party code (Copyright 2009 The Go Authors. All rights reserved.)")
+		assert.Empty(t, buf.String())
+
+		buf.Reset()
+		err = golicense.RunLicenseStdin("foo.go", projectParam, true, false, bytes.NewReader([]byte(thirdPartyContent)), buf)
+		assert.Error(t, err)
+		assert.Equal(t, "1 file is third-party code (has the copyright of another holder):\n\tfoo.go (Copyright 2009 The Go Authors. All rights reserved.)\n", buf.String())
+	})
+
+	_, err := golicense.ParseThirdPartyPolicy("ignore")
+	assert.EqualError(t, err, `invalid third-party policy "ignore": must be one of "add-above", "skip", "add-below" or "fail"`)
+}
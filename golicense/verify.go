@@ -0,0 +1,108 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// problem is a reason for which a file fails verification.
+type problem struct {
+	// descriptions of the problem for a single file and for multiple files
+	singular string
+	plural   string
+}
+
+var (
+	problemIncorrectHeader = problem{
+		singular: "file does not have the correct license header",
+		plural:   "files do not have the correct license header",
+	}
+	problemDuplicateHeader = problem{
+		singular: "file has a duplicated license header",
+		plural:   "files have a duplicated license header",
+	}
+	problemBlankLines = problem{
+		singular: "file does not have the configured number of blank lines after the license header",
+		plural:   "files do not have the configured number of blank lines after the license header",
+	}
+	problemThirdParty = problem{
+		singular: "file is third-party code (has the copyright of another holder)",
+		plural:   "files are third-party code (have the copyright of another holder)",
+	}
+	problemPackageDoc = problem{
+		singular: "file has a license header that is treated as package documentation (add a blank line after it)",
+		plural:   "files have a license header that is treated as package documentation (add a blank line after it)",
+	}
+)
+
+// problems is the order in which the files with each problem are printed.
+var problems = []problem{
+	problemIncorrectHeader,
+	problemDuplicateHeader,
+	problemBlankLines,
+	problemThirdParty,
+	problemPackageDoc,
+}
+
+// verifyResult stores the files that failed verification by problem. Files with an incorrect header are stored along
+// with the classification of the header that they have.
+type verifyResult map[problem][]string
+
+// verify verifies the provided content of the file at the provided path using the provided Licenser and records the
+// problem if it fails verification. Returns whether the content matched the license and whether it failed
+// verification.
+func (r verifyResult) verify(path, content string, licenser Licenser) (bool, bool) {
+	if copyright, ok := thirdPartyCode(licenser, content); ok {
+		r.add(problemThirdParty, fmt.Sprintf("%s (%s)", path, copyright))
+		return false, true
+	}
+	newContent, matched := addLicense(path, licenser, content)
+	if newContent == content {
+		return matched, false
+	}
+	_, duplicate := duplicateLicense(licenser, content)
+	switch {
+	case !matched:
+		r.add(problemIncorrectHeader, fmt.Sprintf("%s (%s)", path, ClassifyHeader(content, licenser)))
+	case duplicate:
+		r.add(problemDuplicateHeader, path)
+	case normalizeBlankLines(licenser, content) != content:
+		r.add(problemBlankLines, fmt.Sprintf("%s (%s)", path, blankLinesDescription(licenser, content)))
+	default:
+		// the only other change that addLicense makes to content that has the license
+		r.add(problemPackageDoc, path)
+	}
+	return matched, true
+}
+
+func (r verifyResult) add(p problem, path string) {
+	r[p] = append(r[p], path)
+}
+
+// print prints the files that failed verification to stdout grouped by problem and returns true if there are no such
+// files.
+func (r verifyResult) print(stdout io.Writer) bool {
+	ok := true
+	for _, p := range problems {
+		files := r[p]
+		if len(files) == 0 {
+			continue
+		}
+		ok = false
+		sort.Strings(files)
+
+		description := p.plural
+		if len(files) == 1 {
+			description = p.singular
+		}
+		parts := append([]string{fmt.Sprintf("%d %s:", len(files), description)}, files...)
+		_, _ = fmt.Fprintln(stdout, strings.Join(parts, "\n\t"))
+	}
+	return ok
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+package golicense
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// writeFileAtomic writes the provided content to the file at path so that the file is never partially written: the
+// content is written to a temporary file in the same directory, which is then renamed to the file. If path is a
+// symbolic link, the file that it refers to is written and the link is kept. The file is written in place instead
+// (as os.WriteFile does) if renaming would change it in a way other than its content: if it has other hard links, if
+// its owner cannot be given to the temporary file, or if the temporary file cannot be created (for example, because
+// the directory is not writable even though the file is).
+func writeFileAtomic(path string, content []byte, perm os.FileMode) (rErr error) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fi, err := os.Stat(target)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if hasOtherLinks(fi) {
+		return writeFileInPlace(target, content, perm)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp")
+	if err != nil {
+		return writeFileInPlace(target, content, perm)
+	}
+	defer func() {
+		if rErr != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+	if err := chownLike(tmpFile, fi); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return writeFileInPlace(target, content, perm)
+	}
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return errors.WithStack(err)
+	}
+	// chmod after chown, which clears the setuid and setgid bits
+	if err := tmpFile.Chmod(perm & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)); err != nil {
+		_ = tmpFile.Close()
+		return errors.WithStack(err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(tmpFile.Name(), target))
+}
+
+func writeFileInPlace(path string, content []byte, perm os.FileMode) error {
+	return errors.WithStack(os.WriteFile(path, content, perm))
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build !unix
+
+package golicense
+
+import (
+	"os"
+)
+
+// hasOtherLinks returns true if the file described by fi has more than one hard link. The number of links is not
+// available on this platform.
+func hasOtherLinks(fi os.FileInfo) bool {
+	return false
+}
+
+// chownLike sets the owner and group of the provided file to those of the file described by fi. Files do not have
+// owners that can be changed on this platform.
+func chownLike(f *os.File, fi os.FileInfo) error {
+	return nil
+}
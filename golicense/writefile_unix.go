@@ -0,0 +1,37 @@
+// Copyright (c) 2016 Palantir Technologies Inc. All rights reserved.
+// Use of this source code is governed by the Apache License, Version 2.0
+// that can be found in the LICENSE file.
+
+//go:build unix
+
+package golicense
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// hasOtherLinks returns true if the file described by fi has more than one hard link.
+func hasOtherLinks(fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && st.Nlink > 1
+}
+
+// chownLike sets the owner and group of the provided file to those of the file described by fi. Returns an error if
+// they differ and cannot be changed.
+func chownLike(f *os.File, fi os.FileInfo) error {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	tmpFi, err := f.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if tmpSt, ok := tmpFi.Sys().(*syscall.Stat_t); ok && tmpSt.Uid == st.Uid && tmpSt.Gid == st.Gid {
+		return nil
+	}
+	return errors.WithStack(f.Chown(int(st.Uid), int(st.Gid)))
+}